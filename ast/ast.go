@@ -1,5 +1,7 @@
 package ast
 
+//go:generate go run gen_children.go
+
 import (
 	"github.com/emilioastarita/gphp/lexer"
 )
@@ -8,6 +10,24 @@ import (
 type Node interface {
 	Parent() Node
 	SetParent(p Node)
+	NodeKind() NodeKind
+	// Children returns the children of the node in field order, each one a
+	// *lexer.Token, a Node, a []Node, a []*lexer.Token or nil when absent.
+	// It returns nil for a nil node.
+	Children() []interface{}
+	// ChildNames returns the names of the children, the keys of Serialize.
+	// The slice is shared and must not be modified.
+	ChildNames() []string
+}
+
+// NodeKind is the type of a node, see children_gen.go.
+type NodeKind int
+
+func (k NodeKind) String() string {
+	if k < 0 || int(k) >= len(nodeKindNames) {
+		return "Invalid"
+	}
+	return nodeKindNames[k]
 }
 
 type NodeOrNodeColl interface{}
@@ -130,8 +150,8 @@ type PrintIntrinsicExpression struct {
 }
 
 type ReservedWord struct {
-	CNode    `serialize:"-"`
-	Children *lexer.Token
+	CNode `serialize:"-"`
+	Child *lexer.Token `serialize:"children"`
 }
 
 type ConstElement struct {
@@ -276,6 +296,11 @@ type InlineHtml struct {
 	ScriptSectionEndTag   *lexer.Token
 	Text                  *lexer.Token
 	ScriptSectionStartTag *lexer.Token
+	// EchoStatement is the implicit echo of a <?= start tag
+	EchoStatement *ExpressionStatement `serialize:"-omitempty"`
+	// ScriptSectionPrependedText is the byte order mark and #! line at
+	// the start of a file
+	ScriptSectionPrependedText *lexer.Token `serialize:"-omitempty"`
 }
 
 // expressions
@@ -451,13 +476,13 @@ type TraitSelectOrAliasClause struct {
 type StringLiteral struct {
 	CNode      `serialize:"-"`
 	StartQuote *lexer.Token
-	Children   NodeOrNodeColl
+	Child      NodeOrNodeColl `serialize:"children"`
 	EndQuote   *lexer.Token
 }
 
 type NumericLiteral struct {
-	CNode    `serialize:"-"`
-	Children *lexer.Token
+	CNode `serialize:"-"`
+	Child *lexer.Token `serialize:"children"`
 }
 
 type ScriptInclusionExpression struct {
@@ -521,7 +546,7 @@ type TernaryExpression struct {
 func (n CNode) Parent() Node {
 	return n.P
 }
-func (n CNode) SetParent(p Node) {
+func (n *CNode) SetParent(p Node) {
 	n.P = p
 }
 
@@ -0,0 +1,186 @@
+// Package build makes trees of the ast package from code, for the code
+// generators. The nodes have the shape the parser gives to the same
+// source and their tokens are synthetic, see lexer.NewSyntheticToken, so
+// they don't need a source and a printer writes them from their kinds
+// and texts.
+//
+//	class := build.Class("User").Extends("Model").
+//		Method(build.Method("name").Returns("string").Body(
+//			build.Return(build.Prop(build.Var("this"), "name")),
+//		)).
+//		Node()
+//	file := build.File(build.Namespace("App"), class)
+//
+// The builders don't add parentheses, an expression whose precedence
+// needs them goes in a Paren. A node given to a builder becomes a child
+// of the node made, it can't be shared by two trees.
+package build
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/emilioastarita/gphp/ast"
+	"github.com/emilioastarita/gphp/lexer"
+)
+
+// kindTexts is the text of the tokens whose kind tells their text, the
+// shortest one for the kinds with several.
+var kindTexts = func() map[lexer.TokenKind]string {
+	texts := map[lexer.TokenKind]string{}
+	for _, m := range []map[string]lexer.TokenKind{lexer.OPERATORS_AND_PUNCTUATORS, lexer.KEYWORDS, lexer.RESERVED_WORDS} {
+		words := make([]string, 0, len(m))
+		for word := range m {
+			words = append(words, word)
+		}
+		sort.Strings(words)
+		for _, word := range words {
+			kind := m[word]
+			if text, ok := texts[kind]; !ok || len(word) < len(text) {
+				texts[kind] = word
+			}
+		}
+	}
+	return texts
+}()
+
+// token returns a synthetic token of a kind with a fixed text, a keyword
+// or a punctuation.
+func token(kind lexer.TokenKind) *lexer.Token {
+	text, ok := kindTexts[kind]
+	if !ok {
+		panic("build: no text for a " + kind.String())
+	}
+	return lexer.NewSyntheticToken(kind, text)
+}
+
+func tokenNode(token *lexer.Token) *ast.TokenNode {
+	return &ast.TokenNode{Token: token}
+}
+
+// adopt sets the parent of the children of a node.
+func adopt(node ast.Node) {
+	for _, child := range node.Children() {
+		switch child := child.(type) {
+		case ast.Node:
+			if child != nil {
+				child.SetParent(node)
+			}
+		case []ast.Node:
+			for _, c := range child {
+				if c != nil {
+					c.SetParent(node)
+				}
+			}
+		}
+	}
+}
+
+// list fills a delimited list with the nodes separated by commas.
+func list(l ast.DelimitedList, nodes []ast.Node) ast.DelimitedList {
+	for i, node := range nodes {
+		if i > 0 {
+			l.AddNode(tokenNode(token(lexer.CommaToken)))
+		}
+		l.AddNode(node)
+	}
+	adopt(l)
+	return l
+}
+
+// Name returns a qualified name, fully qualified when it starts with a
+// backslash.
+func Name(name string) *ast.QualifiedName {
+	n := &ast.QualifiedName{}
+	if strings.HasPrefix(name, `\`) {
+		n.GlobalSpecifier = token(lexer.BackslashToken)
+		name = name[1:]
+	}
+	for i, part := range strings.Split(name, `\`) {
+		if i > 0 {
+			n.NameParts = append(n.NameParts, tokenNode(token(lexer.BackslashToken)))
+		}
+		n.NameParts = append(n.NameParts, tokenNode(lexer.NewSyntheticToken(lexer.Name, part)))
+	}
+	adopt(n)
+	return n
+}
+
+func names(names []string) []ast.Node {
+	nodes := make([]ast.Node, len(names))
+	for i, name := range names {
+		nodes[i] = Name(name)
+	}
+	return nodes
+}
+
+// variableName returns the token of a variable, the $ is optional in name.
+func variableName(name string) *lexer.Token {
+	return lexer.NewSyntheticToken(lexer.VariableName, "$"+strings.TrimPrefix(name, "$"))
+}
+
+// Var returns the variable $name.
+func Var(name string) *ast.Variable {
+	v := &ast.Variable{Name: tokenNode(variableName(name))}
+	adopt(v)
+	return v
+}
+
+// String returns a single quoted string of s.
+func String(s string) *ast.StringLiteral {
+	s = strings.Replace(s, `\`, `\\`, -1)
+	s = strings.Replace(s, `'`, `\'`, -1)
+	return &ast.StringLiteral{Child: lexer.NewSyntheticToken(lexer.StringLiteralToken, "'"+s+"'")}
+}
+
+// Int returns an integer literal, a negative one is negated by a unary
+// minus as it is in the source.
+func Int(i int) ast.Node {
+	if i < 0 {
+		return negate(&ast.NumericLiteral{Child: lexer.NewSyntheticToken(lexer.IntegerLiteralToken, strconv.Itoa(i)[1:])})
+	}
+	return &ast.NumericLiteral{Child: lexer.NewSyntheticToken(lexer.IntegerLiteralToken, strconv.Itoa(i))}
+}
+
+// Float returns a floating point literal, with a fraction or an exponent
+// so it's read back as a float. The infinities and NaN are the constants
+// INF and NAN.
+func Float(f float64) ast.Node {
+	switch {
+	case math.IsNaN(f):
+		return Name("NAN")
+	case math.IsInf(f, 1):
+		return Name("INF")
+	case math.IsInf(f, -1):
+		return negate(Name("INF"))
+	}
+	text := strconv.FormatFloat(f, 'g', -1, 64)
+	if !strings.ContainsAny(text, ".e") {
+		text += ".0"
+	}
+	if strings.HasPrefix(text, "-") {
+		return negate(&ast.NumericLiteral{Child: lexer.NewSyntheticToken(lexer.FloatingLiteralToken, text[1:])})
+	}
+	return &ast.NumericLiteral{Child: lexer.NewSyntheticToken(lexer.FloatingLiteralToken, text)}
+}
+
+func negate(operand ast.Node) ast.Node {
+	n := &ast.UnaryOpExpression{Operator: token(lexer.MinusToken), Operand: operand}
+	adopt(n)
+	return n
+}
+
+// Bool returns true or false.
+func Bool(b bool) *ast.ReservedWord {
+	if b {
+		return &ast.ReservedWord{Child: token(lexer.TrueReservedWord)}
+	}
+	return &ast.ReservedWord{Child: token(lexer.FalseReservedWord)}
+}
+
+// Null returns null.
+func Null() *ast.ReservedWord {
+	return &ast.ReservedWord{Child: token(lexer.NullReservedWord)}
+}
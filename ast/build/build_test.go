@@ -0,0 +1,193 @@
+package build_test
+
+import (
+	"io/ioutil"
+	"math"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/emilioastarita/gphp/ast"
+	"github.com/emilioastarita/gphp/ast/build"
+	"github.com/emilioastarita/gphp/lexer"
+	"github.com/emilioastarita/gphp/parser"
+)
+
+// shape writes the kinds, the fields and the token texts of a tree, what
+// a built tree shares with the tree parsed from the same source. The nil
+// children and the empty lists are left out.
+func shape(child interface{}, source []byte) string {
+	switch child := child.(type) {
+	case *lexer.Token:
+		if child == nil {
+			return ""
+		}
+		return child.Kind.String() + ":" + strconv.Quote(child.Text(source))
+	case ast.Node:
+		children := child.Children()
+		if children == nil {
+			return ""
+		}
+		var b strings.Builder
+		b.WriteString(child.NodeKind().String() + "(")
+		names := child.ChildNames()
+		for i, c := range children {
+			if s := shape(c, source); s != "" {
+				b.WriteString(names[i] + "=" + s + " ")
+			}
+		}
+		b.WriteString(")")
+		return b.String()
+	case []ast.Node:
+		var parts []string
+		for _, node := range child {
+			parts = append(parts, shape(node, source))
+		}
+		if len(parts) == 0 {
+			return ""
+		}
+		return "[" + strings.Join(parts, " ") + "]"
+	case []*lexer.Token:
+		var parts []string
+		for _, token := range child {
+			parts = append(parts, shape(token, source))
+		}
+		if len(parts) == 0 {
+			return ""
+		}
+		return "[" + strings.Join(parts, " ") + "]"
+	}
+	return ""
+}
+
+func checkParents(t *testing.T, name string, node ast.Node) {
+	for _, child := range node.Children() {
+		var nodes []ast.Node
+		switch child := child.(type) {
+		case ast.Node:
+			nodes = []ast.Node{child}
+		case []ast.Node:
+			nodes = child
+		}
+		for _, n := range nodes {
+			if n.Parent() != node {
+				t.Errorf("%s: %s child of %s has parent %v", name, n.NodeKind(), node.NodeKind(), n.Parent())
+			}
+			checkParents(t, name, n)
+		}
+	}
+}
+
+// parseStatement returns the first statement of source, the expression
+// of an expression statement.
+func parseStatement(source string, expression bool) (ast.Node, []byte) {
+	p := parser.Parser{}
+	sourceFile := p.ParseSourceFile([]byte("<?php\n"+source), "")
+	statement := sourceFile.StatementList[1]
+	if expression {
+		return statement.(*ast.ExpressionStatement).Expression[0], sourceFile.FileContents
+	}
+	return statement, sourceFile.FileContents
+}
+
+func TestBuild(t *testing.T) {
+	expressions := []struct {
+		source string
+		node   ast.Node
+	}{
+		{`\Foo\Bar`, build.Name(`\Foo\Bar`)},
+		{`$a`, build.Var("a")},
+		{`'it\'s \\'`, build.String(`it's \`)},
+		{`42`, build.Int(42)},
+		{`-42`, build.Int(-42)},
+		{`1.5`, build.Float(1.5)},
+		{`-2.0`, build.Float(-2)},
+		{`1e+25`, build.Float(1e25)},
+		{`-INF`, build.Float(math.Inf(-1))},
+		{`true`, build.Bool(true)},
+		{`false`, build.Bool(false)},
+		{`null`, build.Null()},
+		{`f()`, build.Call(build.Name("f"))},
+		{`strlen($s, 1)`, build.Call(build.Name("strlen"), build.Var("s"), build.Int(1))},
+		{`$o->m($a)`, build.MethodCall(build.Var("o"), "m", build.Var("a"))},
+		{`A::s()`, build.StaticCall(build.Name("A"), "s")},
+		{`$o->p`, build.Prop(build.Var("o"), "p")},
+		{`A::K`, build.ClassConst(build.Name("A"), "K")},
+		{`new A($x)`, build.New(build.Name("A"), build.Var("x"))},
+		{`new \A()`, build.New(build.Name(`\A`))},
+		{`[]`, build.Array()},
+		{`[1, 'k' => $v]`, build.Array(build.Int(1), build.KeyValue(build.String("k"), build.Var("v")))},
+		{`$a = 1`, build.Assign(build.Var("a"), build.Int(1))},
+		{`$a . 'b'`, build.Binary(build.Var("a"), ".", build.String("b"))},
+		{`$a instanceof B`, build.Binary(build.Var("a"), "instanceof", build.Name("B"))},
+		{`$a += 1`, build.Binary(build.Var("a"), "+=", build.Int(1))},
+		{`($a + $b) * 2`, build.Binary(build.Paren(build.Binary(build.Var("a"), "+", build.Var("b"))), "*", build.Int(2))},
+		{`!$a`, build.Not(build.Var("a"))},
+	}
+	for _, test := range expressions {
+		expected, source := parseStatement(test.source, true)
+		if actual := shape(test.node, nil); actual != shape(expected, source) {
+			t.Errorf("%s: expected\n%s\ngot\n%s", test.source, shape(expected, source), actual)
+		}
+		checkParents(t, test.source, test.node)
+	}
+
+	statements := []struct {
+		source string
+		node   ast.Node
+	}{
+		{`f();`, build.Expr(build.Call(build.Name("f")))},
+		{`echo $a, 'b';`, build.Echo(build.Var("a"), build.String("b"))},
+		{`return;`, build.Return(nil)},
+		{`return $a;`, build.Return(build.Var("a"))},
+		{`throw new E();`, build.Throw(build.New(build.Name("E")))},
+		{`{ f(); }`, build.Block(build.Expr(build.Call(build.Name("f"))))},
+		{`if ($a) { f(); }`, build.If(build.Var("a"), build.Expr(build.Call(build.Name("f")))).Node()},
+		{`if ($a) {} elseif ($b) { g(); } else { h(); }`, build.If(build.Var("a")).
+			ElseIf(build.Var("b"), build.Expr(build.Call(build.Name("g")))).
+			Else(build.Expr(build.Call(build.Name("h")))).Node()},
+		{`namespace App\Models;`, build.Namespace(`App\Models`)},
+		{`use Foo\Bar;`, build.Use(`Foo\Bar`)},
+		{`use Foo\Bar as Baz;`, build.Use(`Foo\Bar`, "Baz")},
+		{`function f() {}`, build.Function("f").Node()},
+		{`function &f(int $a, ?A &$b = null, ...$c): ?string { return $a; }`, build.Function("f").ByRef().
+			Param(build.Param("a").Type("int"), build.Param("b").Type("?A").ByRef().Default(build.Null()), build.Param("c").Variadic()).
+			Returns("?string").
+			Body(build.Return(build.Var("a"))).Node()},
+		{`class A {}`, build.Class("A").Node()},
+		{`abstract class A extends \B implements C, D\E {
+			const X = 1;
+			private static $p = 'a';
+			public $q;
+			public function f(array $a): void { return; }
+			abstract protected static function g();
+			final public function h() {}
+		}`, build.Class("A").Abstract().Extends(`\B`).Implements("C", `D\E`).
+			Const("X", build.Int(1)).
+			Property(build.Property("p").Private().Static().Default(build.String("a"))).
+			Property(build.Property("q")).
+			Method(build.Method("f").Param(build.Param("a").Type("array")).Returns("void").Body(build.Return(nil))).
+			Method(build.Method("g").Protected().Static().Abstract()).
+			Method(build.Method("h").Final()).Node()},
+	}
+	for _, test := range statements {
+		expected, source := parseStatement(test.source, false)
+		if actual := shape(test.node, nil); actual != shape(expected, source) {
+			t.Errorf("%s: expected\n%s\ngot\n%s", test.source, shape(expected, source), actual)
+		}
+		checkParents(t, test.source, test.node)
+	}
+}
+
+func TestFile(t *testing.T) {
+	file := build.File(build.Namespace("App"), build.Expr(build.Call(build.Name("f"))))
+	p := parser.Parser{}
+	expected := p.ParseSourceFile([]byte("<?php\nnamespace App;f();"), "")
+	if actual := shape(file, nil); actual != shape(expected, expected.FileContents) {
+		t.Errorf("expected\n%s\ngot\n%s", shape(expected, expected.FileContents), actual)
+	}
+	checkParents(t, "file", file)
+	if err := ast.Encode(ioutil.Discard, file); err == nil {
+		t.Errorf("expected an error encoding synthetic tokens")
+	}
+}
@@ -0,0 +1,493 @@
+package build
+
+import (
+	"strings"
+
+	"github.com/emilioastarita/gphp/ast"
+	"github.com/emilioastarita/gphp/lexer"
+)
+
+// File returns a php file of the statements, starting with <?php.
+func File(statements ...ast.Node) *ast.SourceFileNode {
+	start := &ast.InlineHtml{ScriptSectionStartTag: lexer.NewSyntheticToken(lexer.ScriptSectionStartTag, "<?php\n")}
+	n := &ast.SourceFileNode{
+		StatementList:  append([]ast.Node{start}, statements...),
+		EndOfFileToken: lexer.NewSyntheticToken(lexer.EndOfFileToken, ""),
+	}
+	adopt(n)
+	return n
+}
+
+// Namespace returns the statement namespace name;.
+func Namespace(name string) *ast.NamespaceDefinition {
+	n := &ast.NamespaceDefinition{
+		NamespaceKeyword:             token(lexer.NamespaceKeyword),
+		Name:                         Name(name),
+		CompoundStatementOrSemicolon: tokenNode(token(lexer.SemicolonToken)),
+	}
+	adopt(n)
+	return n
+}
+
+// Use returns the statement use name;, or use name as alias; when an
+// alias is given.
+func Use(name string, alias ...string) *ast.NamespaceUseDeclaration {
+	clause := &ast.NamespaceUseClause{NamespaceName: Name(name)}
+	if len(alias) > 0 {
+		aliasing := &ast.NamespaceAliasingClause{
+			AsKeyword: token(lexer.AsKeyword),
+			Name:      lexer.NewSyntheticToken(lexer.Name, alias[0]),
+		}
+		clause.NamespaceAliasingClause = aliasing
+	}
+	adopt(clause)
+	n := &ast.NamespaceUseDeclaration{
+		UseKeyword: token(lexer.UseKeyword),
+		UseClauses: list(&ast.NamespaceUseClauseList{}, []ast.Node{clause}),
+		Semicolon:  token(lexer.SemicolonToken),
+	}
+	adopt(n)
+	return n
+}
+
+// modifiers are the keywords of the declarations of a class, written in
+// the order abstract or final, visibility and static.
+type modifiers struct {
+	abstract   bool
+	final      bool
+	visibility lexer.TokenKind
+	static     bool
+}
+
+func (m *modifiers) tokens() []*lexer.Token {
+	var tokens []*lexer.Token
+	if m.abstract {
+		tokens = append(tokens, token(lexer.AbstractKeyword))
+	}
+	if m.final {
+		tokens = append(tokens, token(lexer.FinalKeyword))
+	}
+	if m.visibility != 0 {
+		tokens = append(tokens, token(m.visibility))
+	}
+	if m.static {
+		tokens = append(tokens, token(lexer.StaticKeyword))
+	}
+	return tokens
+}
+
+// builtinTypes are the types that aren't class names.
+var builtinTypes = map[string]lexer.TokenKind{
+	"array":    lexer.ArrayKeyword,
+	"callable": lexer.CallableKeyword,
+	"bool":     lexer.BoolReservedWord,
+	"float":    lexer.FloatReservedWord,
+	"int":      lexer.IntReservedWord,
+	"string":   lexer.StringReservedWord,
+	"object":   lexer.ObjectReservedWord,
+	"void":     lexer.VoidReservedWord,
+}
+
+// typeDeclaration returns the question mark of a nullable type and the
+// type, a builtin one or a class name.
+func typeDeclaration(typ string) (*lexer.Token, ast.Node) {
+	var question *lexer.Token
+	if strings.HasPrefix(typ, "?") {
+		question = token(lexer.QuestionToken)
+		typ = typ[1:]
+	}
+	if kind, ok := builtinTypes[strings.ToLower(typ)]; ok {
+		return question, tokenNode(lexer.NewSyntheticToken(kind, typ))
+	}
+	return question, Name(typ)
+}
+
+// ParamBuilder makes a parameter, see Param.
+type ParamBuilder struct {
+	name         string
+	typ          string
+	defaultValue ast.Node
+	byRef        bool
+	variadic     bool
+}
+
+// Param starts the parameter $name.
+func Param(name string) *ParamBuilder {
+	return &ParamBuilder{name: name}
+}
+
+// Type sets the type, a ? before it makes it nullable.
+func (b *ParamBuilder) Type(typ string) *ParamBuilder {
+	b.typ = typ
+	return b
+}
+
+// Default sets the default value.
+func (b *ParamBuilder) Default(value ast.Node) *ParamBuilder {
+	b.defaultValue = value
+	return b
+}
+
+// ByRef makes the parameter a reference, &$name.
+func (b *ParamBuilder) ByRef() *ParamBuilder {
+	b.byRef = true
+	return b
+}
+
+// Variadic makes the parameter variadic, ...$name.
+func (b *ParamBuilder) Variadic() *ParamBuilder {
+	b.variadic = true
+	return b
+}
+
+// Node returns the parameter.
+func (b *ParamBuilder) Node() *ast.Parameter {
+	n := &ast.Parameter{VariableName: variableName(b.name)}
+	if b.typ != "" {
+		n.QuestionToken, n.TypeDeclaration = typeDeclaration(b.typ)
+	}
+	if b.byRef {
+		n.ByRefToken = token(lexer.AmpersandToken)
+	}
+	if b.variadic {
+		n.DotDotDotToken = token(lexer.DotDotDotToken)
+	}
+	if b.defaultValue != nil {
+		n.EqualsToken = token(lexer.EqualsToken)
+		n.Default = b.defaultValue
+	}
+	adopt(n)
+	return n
+}
+
+// signature is what functions and methods have in common.
+type signature struct {
+	name       string
+	byRef      bool
+	params     []*ParamBuilder
+	returnType string
+	body       []ast.Node
+}
+
+func (s *signature) header() ast.FunctionHeader {
+	header := ast.FunctionHeader{
+		FunctionKeyword: token(lexer.FunctionKeyword),
+		Name:            tokenNode(lexer.NewSyntheticToken(lexer.Name, s.name)),
+		OpenParen:       token(lexer.OpenParenToken),
+		CloseParen:      token(lexer.CloseParenToken),
+	}
+	if s.byRef {
+		header.ByRefToken = token(lexer.AmpersandToken)
+	}
+	if len(s.params) > 0 {
+		params := make([]ast.Node, len(s.params))
+		for i, param := range s.params {
+			params[i] = param.Node()
+		}
+		header.Parameters = list(&ast.ParameterDeclarationList{}, params)
+	}
+	return header
+}
+
+func (s *signature) returnTypeOf() ast.FunctionReturnType {
+	var returnType ast.FunctionReturnType
+	if s.returnType != "" {
+		returnType.ColonToken = token(lexer.ColonToken)
+		returnType.QuestionToken, returnType.ReturnType = typeDeclaration(s.returnType)
+	}
+	return returnType
+}
+
+// FunctionBuilder makes a function declaration, see Function.
+type FunctionBuilder struct {
+	signature
+}
+
+// Function starts the declaration of the function name.
+func Function(name string) *FunctionBuilder {
+	return &FunctionBuilder{signature{name: name}}
+}
+
+// ByRef makes the function return a reference, function &name.
+func (b *FunctionBuilder) ByRef() *FunctionBuilder {
+	b.byRef = true
+	return b
+}
+
+// Param adds parameters.
+func (b *FunctionBuilder) Param(params ...*ParamBuilder) *FunctionBuilder {
+	b.params = append(b.params, params...)
+	return b
+}
+
+// Returns sets the return type, a ? before it makes it nullable.
+func (b *FunctionBuilder) Returns(typ string) *FunctionBuilder {
+	b.returnType = typ
+	return b
+}
+
+// Body adds statements to the body.
+func (b *FunctionBuilder) Body(statements ...ast.Node) *FunctionBuilder {
+	b.body = append(b.body, statements...)
+	return b
+}
+
+// Node returns the function declaration.
+func (b *FunctionBuilder) Node() *ast.FunctionDeclaration {
+	n := &ast.FunctionDeclaration{
+		FunctionHeader:     b.header(),
+		FunctionReturnType: b.returnTypeOf(),
+		FunctionBody:       ast.FunctionBody{CompoundStatementOrSemicolon: Block(b.body...)},
+	}
+	adopt(n)
+	return n
+}
+
+// MethodBuilder makes a method of a class, see Method.
+type MethodBuilder struct {
+	signature
+	modifiers
+}
+
+// Method starts the declaration of the method name, public unless a
+// visibility is set.
+func Method(name string) *MethodBuilder {
+	return &MethodBuilder{signature: signature{name: name}, modifiers: modifiers{visibility: lexer.PublicKeyword}}
+}
+
+// Public makes the method public.
+func (b *MethodBuilder) Public() *MethodBuilder {
+	b.visibility = lexer.PublicKeyword
+	return b
+}
+
+// Protected makes the method protected.
+func (b *MethodBuilder) Protected() *MethodBuilder {
+	b.visibility = lexer.ProtectedKeyword
+	return b
+}
+
+// Private makes the method private.
+func (b *MethodBuilder) Private() *MethodBuilder {
+	b.visibility = lexer.PrivateKeyword
+	return b
+}
+
+// Static makes the method static.
+func (b *MethodBuilder) Static() *MethodBuilder {
+	b.static = true
+	return b
+}
+
+// Abstract makes the method abstract, without a body.
+func (b *MethodBuilder) Abstract() *MethodBuilder {
+	b.abstract = true
+	return b
+}
+
+// Final makes the method final.
+func (b *MethodBuilder) Final() *MethodBuilder {
+	b.final = true
+	return b
+}
+
+// ByRef makes the method return a reference, function &name.
+func (b *MethodBuilder) ByRef() *MethodBuilder {
+	b.byRef = true
+	return b
+}
+
+// Param adds parameters.
+func (b *MethodBuilder) Param(params ...*ParamBuilder) *MethodBuilder {
+	b.params = append(b.params, params...)
+	return b
+}
+
+// Returns sets the return type, a ? before it makes it nullable.
+func (b *MethodBuilder) Returns(typ string) *MethodBuilder {
+	b.returnType = typ
+	return b
+}
+
+// Body adds statements to the body.
+func (b *MethodBuilder) Body(statements ...ast.Node) *MethodBuilder {
+	b.body = append(b.body, statements...)
+	return b
+}
+
+// Node returns the method declaration.
+func (b *MethodBuilder) Node() *ast.MethodDeclaration {
+	n := &ast.MethodDeclaration{
+		FunctionHeader:     b.header(),
+		FunctionReturnType: b.returnTypeOf(),
+		Modifiers:          b.tokens(),
+	}
+	if b.abstract {
+		n.CompoundStatementOrSemicolon = tokenNode(token(lexer.SemicolonToken))
+	} else {
+		n.CompoundStatementOrSemicolon = Block(b.body...)
+	}
+	adopt(n)
+	return n
+}
+
+// PropertyBuilder makes a property of a class, see Property.
+type PropertyBuilder struct {
+	name         string
+	defaultValue ast.Node
+	modifiers
+}
+
+// Property starts the declaration of the property $name, public unless a
+// visibility is set.
+func Property(name string) *PropertyBuilder {
+	return &PropertyBuilder{name: name, modifiers: modifiers{visibility: lexer.PublicKeyword}}
+}
+
+// Public makes the property public.
+func (b *PropertyBuilder) Public() *PropertyBuilder {
+	b.visibility = lexer.PublicKeyword
+	return b
+}
+
+// Protected makes the property protected.
+func (b *PropertyBuilder) Protected() *PropertyBuilder {
+	b.visibility = lexer.ProtectedKeyword
+	return b
+}
+
+// Private makes the property private.
+func (b *PropertyBuilder) Private() *PropertyBuilder {
+	b.visibility = lexer.PrivateKeyword
+	return b
+}
+
+// Static makes the property static.
+func (b *PropertyBuilder) Static() *PropertyBuilder {
+	b.static = true
+	return b
+}
+
+// Default sets the default value.
+func (b *PropertyBuilder) Default(value ast.Node) *PropertyBuilder {
+	b.defaultValue = value
+	return b
+}
+
+// Node returns the property declaration.
+func (b *PropertyBuilder) Node() *ast.PropertyDeclaration {
+	var element ast.Node = Var(b.name)
+	if b.defaultValue != nil {
+		element = Assign(element, b.defaultValue)
+	}
+	n := &ast.PropertyDeclaration{
+		Modifiers:        b.tokens(),
+		PropertyElements: list(&ast.ExpressionList{}, []ast.Node{element}),
+		Semicolon:        token(lexer.SemicolonToken),
+	}
+	adopt(n)
+	return n
+}
+
+// ClassBuilder makes a class declaration, see Class.
+type ClassBuilder struct {
+	name       string
+	modifier   lexer.TokenKind
+	extends    string
+	implements []string
+	members    []func() ast.Node
+}
+
+// Class starts the declaration of the class name.
+func Class(name string) *ClassBuilder {
+	return &ClassBuilder{name: name}
+}
+
+// Abstract makes the class abstract.
+func (b *ClassBuilder) Abstract() *ClassBuilder {
+	b.modifier = lexer.AbstractKeyword
+	return b
+}
+
+// Final makes the class final.
+func (b *ClassBuilder) Final() *ClassBuilder {
+	b.modifier = lexer.FinalKeyword
+	return b
+}
+
+// Extends sets the parent class.
+func (b *ClassBuilder) Extends(name string) *ClassBuilder {
+	b.extends = name
+	return b
+}
+
+// Implements adds interfaces.
+func (b *ClassBuilder) Implements(names ...string) *ClassBuilder {
+	b.implements = append(b.implements, names...)
+	return b
+}
+
+// Const adds the constant const name = value;.
+func (b *ClassBuilder) Const(name string, value ast.Node) *ClassBuilder {
+	b.members = append(b.members, func() ast.Node {
+		element := &ast.ConstElement{
+			Name:        lexer.NewSyntheticToken(lexer.Name, name),
+			EqualsToken: token(lexer.EqualsToken),
+			Assignment:  value,
+		}
+		adopt(element)
+		n := &ast.ClassConstDeclaration{
+			ConstKeyword:  token(lexer.ConstKeyword),
+			ConstElements: list(&ast.ConstElementList{}, []ast.Node{element}),
+			Semicolon:     token(lexer.SemicolonToken),
+		}
+		adopt(n)
+		return n
+	})
+	return b
+}
+
+// Property adds a property.
+func (b *ClassBuilder) Property(property *PropertyBuilder) *ClassBuilder {
+	b.members = append(b.members, func() ast.Node { return property.Node() })
+	return b
+}
+
+// Method adds a method.
+func (b *ClassBuilder) Method(method *MethodBuilder) *ClassBuilder {
+	b.members = append(b.members, func() ast.Node { return method.Node() })
+	return b
+}
+
+// Node returns the class declaration, its members in the order they were
+// added.
+func (b *ClassBuilder) Node() *ast.ClassDeclaration {
+	n := &ast.ClassDeclaration{
+		ClassKeyword: token(lexer.ClassKeyword),
+		Name:         lexer.NewSyntheticToken(lexer.Name, b.name),
+	}
+	if b.modifier != 0 {
+		n.AbstractOrFinalModifier = token(b.modifier)
+	}
+	if b.extends != "" {
+		base := &ast.ClassBaseClause{ExtendsKeyword: token(lexer.ExtendsKeyword), BaseClass: Name(b.extends)}
+		adopt(base)
+		n.ClassBaseClause = base
+	}
+	if len(b.implements) > 0 {
+		interfaces := &ast.ClassInterfaceClause{
+			ImplementsKeyword: token(lexer.ImplementsKeyword),
+			InterfaceNameList: list(&ast.QualifiedNameList{}, names(b.implements)),
+		}
+		adopt(interfaces)
+		n.ClassInterfaceClause = interfaces
+	}
+	members := &ast.ClassMembersNode{OpenBrace: token(lexer.OpenBraceToken), CloseBrace: token(lexer.CloseBraceToken)}
+	for _, member := range b.members {
+		members.ClassMemberDeclarations = append(members.ClassMemberDeclarations, member())
+	}
+	adopt(members)
+	n.ClassMembers = members
+	adopt(n)
+	return n
+}
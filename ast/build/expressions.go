@@ -0,0 +1,161 @@
+package build
+
+import (
+	"github.com/emilioastarita/gphp/ast"
+	"github.com/emilioastarita/gphp/lexer"
+)
+
+// arguments returns the list of the arguments of a call, nil without
+// arguments as the parser leaves it.
+func arguments(args []ast.Node) ast.Node {
+	if len(args) == 0 {
+		return nil
+	}
+	nodes := make([]ast.Node, len(args))
+	for i, arg := range args {
+		argument := &ast.ArgumentExpression{Expression: arg}
+		adopt(argument)
+		nodes[i] = argument
+	}
+	return list(&ast.ArgumentExpressionList{}, nodes)
+}
+
+// Call returns the call of a function, fn is usually a Name.
+func Call(fn ast.Node, args ...ast.Node) *ast.CallExpression {
+	n := &ast.CallExpression{
+		CallableExpression:     fn,
+		OpenParen:              token(lexer.OpenParenToken),
+		ArgumentExpressionList: arguments(args),
+		CloseParen:             token(lexer.CloseParenToken),
+	}
+	adopt(n)
+	return n
+}
+
+// MethodCall returns the call $object->name(args...).
+func MethodCall(object ast.Node, name string, args ...ast.Node) *ast.CallExpression {
+	return Call(Prop(object, name), args...)
+}
+
+// StaticCall returns the call Class::name(args...).
+func StaticCall(class ast.Node, name string, args ...ast.Node) *ast.CallExpression {
+	return Call(ClassConst(class, name), args...)
+}
+
+// Prop returns the property access $object->name.
+func Prop(object ast.Node, name string) *ast.MemberAccessExpression {
+	n := &ast.MemberAccessExpression{
+		DereferencableExpression: object,
+		ArrowToken:               token(lexer.ArrowToken),
+		MemberName:               tokenNode(lexer.NewSyntheticToken(lexer.Name, name)),
+	}
+	adopt(n)
+	return n
+}
+
+// ClassConst returns the constant access Class::name, also the callee of
+// a static call.
+func ClassConst(class ast.Node, name string) *ast.ScopedPropertyAccessExpression {
+	n := &ast.ScopedPropertyAccessExpression{
+		ScopeResolutionQualifier: class,
+		DoubleColon:              token(lexer.ColonColonToken),
+		MemberName:               tokenNode(lexer.NewSyntheticToken(lexer.Name, name)),
+	}
+	adopt(n)
+	return n
+}
+
+// New returns the creation new Class(args...).
+func New(class ast.Node, args ...ast.Node) *ast.ObjectCreationExpression {
+	n := &ast.ObjectCreationExpression{
+		NewKeword:              token(lexer.NewKeyword),
+		ClassTypeDesignator:    class,
+		OpenParen:              token(lexer.OpenParenToken),
+		ArgumentExpressionList: arguments(args),
+		CloseParen:             token(lexer.CloseParenToken),
+	}
+	adopt(n)
+	return n
+}
+
+// Array returns a short array of the elements, the ones that aren't a
+// KeyValue are the values of the next keys.
+func Array(elements ...ast.Node) *ast.ArrayCreationExpression {
+	n := &ast.ArrayCreationExpression{
+		OpenParenOrBracket:  token(lexer.OpenBracketToken),
+		CloseParenOrBracket: token(lexer.CloseBracketToken),
+	}
+	if len(elements) > 0 {
+		nodes := make([]ast.Node, len(elements))
+		for i, element := range elements {
+			if _, ok := element.(*ast.ArrayElement); !ok {
+				value := &ast.ArrayElement{ElementValue: element}
+				adopt(value)
+				element = value
+			}
+			nodes[i] = element
+		}
+		n.ArrayElements = list(&ast.ArrayElementList{}, nodes)
+	}
+	adopt(n)
+	return n
+}
+
+// KeyValue returns the element key => value of an Array.
+func KeyValue(key, value ast.Node) *ast.ArrayElement {
+	n := &ast.ArrayElement{
+		ElementKey:   key,
+		ArrowToken:   token(lexer.DoubleArrowToken),
+		ElementValue: value,
+	}
+	adopt(n)
+	return n
+}
+
+// Assign returns the assignment left = right.
+func Assign(left, right ast.Node) *ast.AssignmentExpression {
+	n := &ast.AssignmentExpression{}
+	n.LeftOperand = left
+	n.Operator = token(lexer.EqualsToken)
+	n.RightOperand = right
+	adopt(n)
+	return n
+}
+
+// Binary returns the expression left operator right, the operator is its
+// text, like "+", "." or "instanceof". The compound assignments, "+=",
+// are binary expressions too.
+func Binary(left ast.Node, operator string, right ast.Node) *ast.BinaryExpression {
+	kind, ok := lexer.OPERATORS_AND_PUNCTUATORS[operator]
+	if !ok {
+		kind, ok = lexer.KEYWORDS[operator]
+	}
+	if !ok {
+		panic("build: unknown operator " + operator)
+	}
+	n := &ast.BinaryExpression{
+		LeftOperand:  left,
+		Operator:     lexer.NewSyntheticToken(kind, operator),
+		RightOperand: right,
+	}
+	adopt(n)
+	return n
+}
+
+// Not returns the negation !operand.
+func Not(operand ast.Node) *ast.UnaryOpExpression {
+	n := &ast.UnaryOpExpression{Operator: token(lexer.ExclamationToken), Operand: operand}
+	adopt(n)
+	return n
+}
+
+// Paren returns the expression in parentheses.
+func Paren(expression ast.Node) *ast.ParenthesizedExpression {
+	n := &ast.ParenthesizedExpression{
+		OpenParen:  token(lexer.OpenParenToken),
+		Expression: expression,
+		CloseParen: token(lexer.CloseParenToken),
+	}
+	adopt(n)
+	return n
+}
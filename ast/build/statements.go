@@ -0,0 +1,112 @@
+package build
+
+import (
+	"github.com/emilioastarita/gphp/ast"
+	"github.com/emilioastarita/gphp/lexer"
+)
+
+// Expr returns the statement of an expression.
+func Expr(expression ast.Node) *ast.ExpressionStatement {
+	n := &ast.ExpressionStatement{Expression: []ast.Node{expression}, Semicolon: token(lexer.SemicolonToken)}
+	adopt(n)
+	return n
+}
+
+// Echo returns the statement echo expressions....
+func Echo(expressions ...ast.Node) *ast.ExpressionStatement {
+	echo := &ast.EchoExpression{
+		EchoKeyword: token(lexer.EchoKeyword),
+		Expressions: list(&ast.ExpressionList{}, expressions),
+	}
+	adopt(echo)
+	return Expr(echo)
+}
+
+// Return returns the statement return expression, a nil expression
+// returns nothing.
+func Return(expression ast.Node) *ast.ReturnStatement {
+	n := &ast.ReturnStatement{ReturnKeyword: token(lexer.ReturnKeyword), Semicolon: token(lexer.SemicolonToken)}
+	if expression != nil {
+		n.Expression = expression
+	}
+	adopt(n)
+	return n
+}
+
+// Throw returns the statement throw expression.
+func Throw(expression ast.Node) *ast.ThrowStatement {
+	n := &ast.ThrowStatement{
+		ThrowKeyword: token(lexer.ThrowKeyword),
+		Expression:   expression,
+		Semicolon:    token(lexer.SemicolonToken),
+	}
+	adopt(n)
+	return n
+}
+
+// Block returns the statements in braces.
+func Block(statements ...ast.Node) *ast.CompoundStatementNode {
+	n := &ast.CompoundStatementNode{
+		OpenBrace:  token(lexer.OpenBraceToken),
+		Statements: statements,
+		CloseBrace: token(lexer.CloseBraceToken),
+	}
+	adopt(n)
+	return n
+}
+
+// IfBuilder makes an if statement with braces, see If.
+type IfBuilder struct {
+	condition  ast.Node
+	statements []ast.Node
+	elseIfs    []*ast.ElseIfClauseNode
+	elseClause *ast.ElseClauseNode
+}
+
+// If starts the statement if (condition) { statements }.
+func If(condition ast.Node, statements ...ast.Node) *IfBuilder {
+	return &IfBuilder{condition: condition, statements: statements}
+}
+
+// ElseIf adds an elseif (condition) { statements }.
+func (b *IfBuilder) ElseIf(condition ast.Node, statements ...ast.Node) *IfBuilder {
+	n := &ast.ElseIfClauseNode{
+		ElseIfKeyword: token(lexer.ElseIfKeyword),
+		OpenParen:     token(lexer.OpenParenToken),
+		Expression:    condition,
+		CloseParen:    token(lexer.CloseParenToken),
+		Statements:    ast.Node(Block(statements...)),
+	}
+	adopt(n)
+	b.elseIfs = append(b.elseIfs, n)
+	return b
+}
+
+// Else sets the else { statements }.
+func (b *IfBuilder) Else(statements ...ast.Node) *IfBuilder {
+	b.elseClause = &ast.ElseClauseNode{
+		ElseKeyword: token(lexer.ElseKeyword),
+		Statements:  ast.Node(Block(statements...)),
+	}
+	adopt(b.elseClause)
+	return b
+}
+
+// Node returns the if statement.
+func (b *IfBuilder) Node() *ast.IfStatementNode {
+	n := &ast.IfStatementNode{
+		IfKeyword:  token(lexer.IfKeyword),
+		OpenParen:  token(lexer.OpenParenToken),
+		Expression: b.condition,
+		CloseParen: token(lexer.CloseParenToken),
+		Statements: ast.Node(Block(b.statements...)),
+	}
+	for _, elseIf := range b.elseIfs {
+		n.ElseIfClauses = append(n.ElseIfClauses, elseIf)
+	}
+	if b.elseClause != nil {
+		n.ElseClause = b.elseClause
+	}
+	adopt(n)
+	return n
+}
@@ -0,0 +1,86 @@
+package ast_test
+
+import (
+	"io/ioutil"
+	"reflect"
+	"testing"
+
+	"github.com/emilioastarita/gphp/ast"
+	"github.com/emilioastarita/gphp/lexer"
+	"github.com/emilioastarita/gphp/parser"
+)
+
+// TestChildren checks the generated methods of the nodes of complex.php.
+func TestChildren(t *testing.T) {
+	data, err := ioutil.ReadFile("../lexer/cases/complex.php")
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := parser.Parser{}
+	sourceFile := p.ParseSourceFile(data, "")
+
+	kinds := map[ast.NodeKind]bool{}
+	var walk func(child interface{})
+	walk = func(child interface{}) {
+		switch child := child.(type) {
+		case nil, *lexer.Token:
+		case ast.Node:
+			kind := child.NodeKind()
+			kinds[kind] = true
+			if name := reflect.TypeOf(child).Elem().Name(); kind.String() != name {
+				t.Errorf("%s has kind %s", name, kind)
+			}
+			children := child.Children()
+			if len(children) != len(child.ChildNames()) {
+				t.Errorf("%s has %d children and %d names", kind, len(children), len(child.ChildNames()))
+			}
+			for _, c := range children {
+				walk(c)
+			}
+		case []ast.Node:
+			for _, node := range child {
+				walk(node)
+			}
+		case []*lexer.Token:
+			for _, token := range child {
+				if token == nil {
+					t.Errorf("nil token in a list")
+				}
+			}
+		default:
+			t.Errorf("unexpected child %T", child)
+		}
+	}
+	walk(sourceFile)
+	if len(kinds) < 50 {
+		t.Errorf("expected most kinds in complex.php, got %d", len(kinds))
+	}
+
+	var nilNode *ast.ClassDeclaration
+	if nilNode.Children() != nil || nilNode.NodeKind() != ast.KindClassDeclaration {
+		t.Errorf("a nil node should have no children and its kind")
+	}
+	if ast.NodeKind(-1).String() != "Invalid" || ast.KindInvalid.String() != "Invalid" {
+		t.Errorf("unexpected names of invalid kinds")
+	}
+}
+
+// TestSerializeValues checks the values Serialize writes as nil and the
+// ones it rejects.
+func TestSerializeValues(t *testing.T) {
+	if ast.Serialize(nil) != nil || ast.Serialize((*ast.Variable)(nil)) != nil || ast.Serialize((*lexer.Token)(nil)) != nil {
+		t.Error("expected nil values serialized as nil")
+	}
+	p := parser.Parser{}
+	sourceFile := p.ParseSourceFile([]byte("<?php $a;"), "")
+	for _, value := range []interface{}{&sourceFile, *sourceFile, 1} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("expected Serialize to panic on a %T", value)
+				}
+			}()
+			ast.Serialize(value)
+		}()
+	}
+}
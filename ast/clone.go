@@ -0,0 +1,246 @@
+package ast
+
+import "github.com/emilioastarita/gphp/lexer"
+
+// Clone returns a deep copy of a node, its nodes and tokens are new and its
+// children have the copies as parents. The copy has no parent. A cloned
+// SourceFileNode shares the FileContents of the node, and the tokens of a
+// node cloned from a file hold its text, see lexer.Token.WithSource, so
+// the copy is printed and compared without the file.
+func Clone(n Node) Node {
+	if n == nil || n.Children() == nil {
+		return nil
+	}
+	c := cloner{}
+	if source := fileContents(n); source != nil {
+		text := string(source)
+		c.source = &text
+	}
+	return c.node(n, nil)
+}
+
+type cloner struct {
+	// the text of the file of the cloned node
+	source *string
+}
+
+func (c *cloner) node(n Node, parent Node) Node {
+	copied := newNode(n.NodeKind())
+	copied.SetParent(parent)
+	if file, ok := n.(*SourceFileNode); ok {
+		copied.(*SourceFileNode).FileContents = file.FileContents
+		copied.(*SourceFileNode).Uri = file.Uri
+	}
+	for i, child := range n.Children() {
+		if child = c.child(child, copied); child != nil {
+			copied.(childSetter).setChild(i, child)
+		}
+	}
+	return copied
+}
+
+func (c *cloner) token(token *lexer.Token) *lexer.Token {
+	copied := *token
+	if c.source != nil && !token.HasSource() {
+		copied = token.WithSource(c.source)
+	}
+	return &copied
+}
+
+func (c *cloner) child(child interface{}, parent Node) interface{} {
+	switch child := child.(type) {
+	case *lexer.Token:
+		if child == nil {
+			return nil
+		}
+		return c.token(child)
+	case Node:
+		if child == nil || child.Children() == nil {
+			return nil
+		}
+		return c.node(child, parent)
+	case []Node:
+		if child == nil {
+			return nil
+		}
+		nodes := make([]Node, len(child))
+		for i, node := range child {
+			if node, ok := c.child(node, parent).(Node); ok {
+				nodes[i] = node
+			}
+		}
+		return nodes
+	case []*lexer.Token:
+		if child == nil {
+			return nil
+		}
+		tokens := make([]*lexer.Token, len(child))
+		for i, token := range child {
+			if token != nil {
+				tokens[i] = c.token(token)
+			}
+		}
+		return tokens
+	}
+	return nil
+}
+
+// EqualMode is what Equal compares.
+type EqualMode int
+
+const (
+	// EqualStructure compares the kinds of the nodes and the kinds and the
+	// texts of the tokens, not their positions nor their trivia. A
+	// synthetic token is equal to a token read with the same text, so a
+	// built tree is equal to the tree parsed from its source. A TokenNode,
+	// a Missing and a SkippedNode are compared by the token they hold.
+	EqualStructure EqualMode = iota
+	// EqualStrict compares also the positions of the tokens, their
+	// categories and their leading trivia.
+	EqualStrict
+)
+
+// Equal tells if two nodes have the same children. The text of a token is
+// read from the token when it holds it, a synthetic token or a token of a
+// clone, else from the FileContents of the SourceFileNode holding its
+// node. The tokens without a text, e.g. of a tree read back without its
+// source, are compared by their kinds and lengths. A nil list and an empty one are equal, as are a nil node and a
+// TokenNode without a token.
+func Equal(a, b Node, mode EqualMode) bool {
+	e := equality{mode: mode, sourceA: fileContents(a), sourceB: fileContents(b)}
+	return e.child(a, b)
+}
+
+// fileContents returns the FileContents of the file holding a node.
+func fileContents(n Node) []byte {
+	if n == nil || n.Children() == nil {
+		return nil
+	}
+	for ; n != nil; n = n.Parent() {
+		if file, ok := n.(*SourceFileNode); ok {
+			return file.FileContents
+		}
+	}
+	return nil
+}
+
+type equality struct {
+	mode             EqualMode
+	sourceA, sourceB []byte
+}
+
+func (e *equality) child(a, b interface{}) bool {
+	switch a := a.(type) {
+	case nil:
+		return isNil(b)
+	case *lexer.Token:
+		b, ok := b.(*lexer.Token)
+		if a == nil || !ok || b == nil {
+			return isNil(a) && isNil(b)
+		}
+		return e.token(a, b)
+	case Node:
+		b, ok := b.(Node)
+		if isNil(a) || !ok || isNil(b) {
+			return isNil(a) && isNil(b)
+		}
+		if e.mode == EqualStructure {
+			// a missing or skipped token is the same wrapped in any node
+			tokenA, okA := a.(NodeWithToken)
+			tokenB, okB := b.(NodeWithToken)
+			if okA && okB {
+				return e.token(tokenA.GetToken(), tokenB.GetToken())
+			}
+		}
+		if a.NodeKind() != b.NodeKind() {
+			return false
+		}
+		childrenA, childrenB := a.Children(), b.Children()
+		for i := range childrenA {
+			if !e.child(childrenA[i], childrenB[i]) {
+				return false
+			}
+		}
+		return true
+	case []Node:
+		b, ok := b.([]Node)
+		if !ok {
+			return len(a) == 0 && isNil(b)
+		}
+		if len(a) != len(b) {
+			return false
+		}
+		for i := range a {
+			if !e.child(a[i], b[i]) {
+				return false
+			}
+		}
+		return true
+	case []*lexer.Token:
+		b, ok := b.([]*lexer.Token)
+		if !ok {
+			return len(a) == 0 && isNil(b)
+		}
+		if len(a) != len(b) {
+			return false
+		}
+		for i := range a {
+			if !e.child(a[i], b[i]) {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// isNil tells if a child is absent: nil, a nil node or token, a node
+// wrapping no token, or an empty list.
+func isNil(child interface{}) bool {
+	switch child := child.(type) {
+	case nil:
+		return true
+	case *lexer.Token:
+		return child == nil
+	case NodeWithToken:
+		return child.Children() == nil || child.GetToken() == nil
+	case Node:
+		return child.Children() == nil
+	case []Node:
+		return len(child) == 0
+	case []*lexer.Token:
+		return len(child) == 0
+	}
+	return false
+}
+
+func (e *equality) token(a, b *lexer.Token) bool {
+	if a.Kind != b.Kind {
+		return false
+	}
+	if e.mode == EqualStrict {
+		if a.Cat != b.Cat || a.FullStart != b.FullStart || a.Start != b.Start || a.Length != b.Length {
+			return false
+		}
+	} else if (a.Cat == lexer.TokenCatMissing || a.Cat == lexer.TokenCatSkipped || b.Cat == lexer.TokenCatMissing || b.Cat == lexer.TokenCatSkipped) && a.Cat != b.Cat {
+		return false
+	}
+	textA, okA := tokenText(a, e.sourceA, e.mode == EqualStrict)
+	textB, okB := tokenText(b, e.sourceB, e.mode == EqualStrict)
+	if okA && okB {
+		return textA == textB
+	}
+	return a.Length-(a.Start-a.FullStart) == b.Length-(b.Start-b.FullStart)
+}
+
+// tokenText returns the text of a token, with its leading trivia when full
+// is set, false when the token isn't in the source.
+func tokenText(token *lexer.Token, source []byte, full bool) (string, bool) {
+	if !token.HasSource() && (token.FullStart < 0 || token.FullStart+token.Length > len(source)) {
+		return "", false
+	}
+	if full {
+		return token.FullText(source), true
+	}
+	return token.Text(source), true
+}
@@ -0,0 +1,164 @@
+package ast_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/emilioastarita/gphp/ast"
+	"github.com/emilioastarita/gphp/ast/build"
+	"github.com/emilioastarita/gphp/lexer"
+	"github.com/emilioastarita/gphp/parser"
+)
+
+// TestClone clones the trees of the parser cases, the clones are equal to
+// them and share no node nor token.
+func TestClone(t *testing.T) {
+	sourceFiles, _ := filepath.Glob("../parser/cases/*.php")
+	for _, sourceFileName := range sourceFiles {
+		source, _ := ioutil.ReadFile(sourceFileName)
+		p := parser.Parser{}
+		sourceFile := p.ParseSourceFile(source, sourceFileName)
+		clone := ast.Clone(sourceFile)
+		if !ast.Equal(sourceFile, clone, ast.EqualStrict) {
+			t.Errorf("%s: the clone differs", sourceFileName)
+		}
+		if clone.Parent() != nil {
+			t.Errorf("%s: the clone has a parent", sourceFileName)
+		}
+		checkParents(t, sourceFileName, clone)
+		checkShared(t, sourceFileName, sourceFile, clone)
+	}
+	if ast.Clone(nil) != nil || ast.Clone((*ast.Variable)(nil)) != nil {
+		t.Error("expected nil clones of nil nodes")
+	}
+}
+
+// TestCloneSubtree clones statements out of their file, the clones keep the
+// texts of their tokens.
+func TestCloneSubtree(t *testing.T) {
+	p := parser.Parser{}
+	sourceFile := p.ParseSourceFile([]byte("<?php $aa = 1; $bb = 2; $aa = 1;"), "")
+	a, b, c := sourceFile.StatementList[1], sourceFile.StatementList[2], sourceFile.StatementList[3]
+	for _, mode := range []ast.EqualMode{ast.EqualStructure, ast.EqualStrict} {
+		if ast.Equal(a, ast.Clone(b), mode) || ast.Equal(ast.Clone(a), ast.Clone(b), mode) {
+			t.Errorf("mode %d: expected the clones of statements of the same length to differ", mode)
+		}
+		if !ast.Equal(a, ast.Clone(a), mode) || !ast.Equal(ast.Clone(a), ast.Clone(a), mode) {
+			t.Errorf("mode %d: expected a statement equal to its clone", mode)
+		}
+	}
+	if !ast.Equal(ast.Clone(a), ast.Clone(c), ast.EqualStructure) || ast.Equal(ast.Clone(a), ast.Clone(c), ast.EqualStrict) {
+		t.Error("expected the clones of the same statement at two positions structurally equal only")
+	}
+	var buf bytes.Buffer
+	ast.FprintTree(&buf, ast.Clone(b), nil)
+	if !strings.Contains(buf.String(), `"$bb"`) {
+		t.Errorf("expected the texts of the clone in its dump:\n%s", buf.String())
+	}
+}
+
+// checkShared checks that a tree and its clone have no node nor token in
+// common.
+func checkShared(t *testing.T, golden string, a, b interface{}) {
+	switch a := a.(type) {
+	case *lexer.Token:
+		if a != nil && a == b.(*lexer.Token) {
+			t.Errorf("%s: shared %s token", golden, a.Kind)
+		}
+	case ast.Node:
+		if a == b {
+			t.Errorf("%s: shared %s", golden, a.NodeKind())
+		}
+		childrenA, childrenB := a.Children(), b.(ast.Node).Children()
+		for i := range childrenA {
+			checkShared(t, golden, childrenA[i], childrenB[i])
+		}
+	case []ast.Node:
+		for i := range a {
+			checkShared(t, golden, a[i], b.([]ast.Node)[i])
+		}
+	case []*lexer.Token:
+		for i := range a {
+			checkShared(t, golden, a[i], b.([]*lexer.Token)[i])
+		}
+	}
+}
+
+// TestEqualRoundTrip checks that the trees of the parser cases are
+// structurally equal to the trees read back from their json, where a
+// closure without a name or a missing token wrapped in a TokenNode are
+// written like a nil name and a Missing.
+func TestEqualRoundTrip(t *testing.T) {
+	sourceFiles, _ := filepath.Glob("../parser/cases/*.php")
+	recoveryFiles, _ := filepath.Glob("../parser/recovery/*.php")
+	sourceFiles = append(sourceFiles, recoveryFiles...)
+	for _, sourceFileName := range sourceFiles {
+		source, _ := ioutil.ReadFile(sourceFileName)
+		p := parser.Parser{}
+		sourceFile := p.ParseSourceFile(source, sourceFileName)
+		data, _ := json.Marshal(ast.Serialize(sourceFile))
+		deserialized, err := ast.Deserialize(data)
+		if err != nil {
+			t.Errorf("%s: %v", sourceFileName, err)
+			continue
+		}
+		deserialized.FileContents = source
+		if !ast.Equal(sourceFile, deserialized, ast.EqualStructure) {
+			t.Errorf("%s: the deserialized tree differs", sourceFileName)
+		}
+	}
+}
+
+func TestEqual(t *testing.T) {
+	parse := func(source string) ast.Node {
+		p := parser.Parser{}
+		return p.ParseSourceFile([]byte(source), "")
+	}
+	tests := []struct {
+		a, b              string
+		structure, strict bool
+	}{
+		{"<?php f($a, 1);", "<?php f($a, 1);", true, true},
+		{"<?php f( $a ,1);", "<?php f($a, 1);", true, false},
+		{"<?php f($a, 1);", "<?php  f($a, 1);", true, false},
+		{"<?php f($a, 1);", "<?php f($a, 2);", false, false},
+		{"<?php f($a);", "<?php f($a, 1);", false, false},
+		{"<?php f($a);", "<?php f($a", false, false},
+		{"<?php if ($a) { f(); }", "<?php if ($a): f(); endif;", false, false},
+		{"<?php if ($a): f(); endif;", "<?php if ($a): g(); endif;", false, false},
+		{"<?php if ($a): f(); endif;", "<?php if ($a):  f(); endif;", true, false},
+	}
+	for _, test := range tests {
+		a, b := parse(test.a), parse(test.b)
+		if ast.Equal(a, b, ast.EqualStructure) != test.structure {
+			t.Errorf("%q and %q: expected structurally equal %v", test.a, test.b, test.structure)
+		}
+		if ast.Equal(a, b, ast.EqualStrict) != test.strict {
+			t.Errorf("%q and %q: expected strictly equal %v", test.a, test.b, test.strict)
+		}
+	}
+
+	// a built statement is equal to the parsed one
+	parsed := parse("<?php f($a, 'b');").(*ast.SourceFileNode).StatementList[1]
+	built := build.Expr(build.Call(build.Name("f"), build.Var("a"), build.String("b")))
+	if !ast.Equal(parsed, built, ast.EqualStructure) || ast.Equal(parsed, built, ast.EqualStrict) {
+		t.Error("expected a built statement structurally equal to the parsed one only")
+	}
+	if ast.Equal(parsed, build.Expr(build.Call(build.Name("f"), build.Var("a"), build.String("c"))), ast.EqualStructure) {
+		t.Error("expected the texts of the tokens compared")
+	}
+	if !ast.Equal(nil, (*ast.Variable)(nil), ast.EqualStrict) || ast.Equal(parsed, nil, ast.EqualStructure) {
+		t.Error("expected only the nil nodes equal to nil")
+	}
+	if !ast.Equal(&ast.TokenNode{}, nil, ast.EqualStrict) || !ast.Equal(nil, &ast.TokenNode{}, ast.EqualStructure) {
+		t.Error("expected a TokenNode without a token equal to nil")
+	}
+	missing := &lexer.Token{Kind: lexer.SemicolonToken, FullStart: 3, Start: 3, Cat: lexer.TokenCatMissing}
+	if !ast.Equal(&ast.TokenNode{Token: missing}, &ast.Missing{Token: missing}, ast.EqualStructure) || ast.Equal(&ast.TokenNode{Token: missing}, &ast.Missing{Token: missing}, ast.EqualStrict) {
+		t.Error("expected the nodes holding the same missing token structurally equal only")
+	}
+}
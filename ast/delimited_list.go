@@ -3,7 +3,7 @@ package ast
 type DelimitedList interface {
 	Node
 	AddNode(n Node)
-	Children() []Node
+	Elements() []Node
 	Len() int
 }
 
@@ -88,7 +88,7 @@ func (e *ExpressionListChild) AddNode(node Node) {
 	e.Child = append(e.Child, node)
 }
 
-func (e *ExpressionListChild) Children() []Node {
+func (e *ExpressionListChild) Elements() []Node {
 	return e.Child
 }
 
@@ -0,0 +1,266 @@
+package ast
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/emilioastarita/gphp/lexer"
+)
+
+// childType is the go type of a child, it tells Deserialize what to build
+// from its json.
+type childType uint8
+
+const (
+	// childToken is a *lexer.Token
+	childToken childType = iota
+	// childTokens is a []*lexer.Token
+	childTokens
+	// childNode is a Node, a token is wrapped in a TokenNode, a Missing or
+	// a SkippedNode
+	childNode
+	// childNodes is a []Node
+	childNodes
+	// childAny is a NodeOrNodeColl, a token stays a *lexer.Token
+	childAny
+)
+
+// childSetter is implemented by the nodes, see children_gen.go.
+type childSetter interface {
+	setChild(i int, child interface{}) bool
+}
+
+var nodeKindsByName = func() map[string]NodeKind {
+	m := make(map[string]NodeKind, len(nodeKindNames))
+	for kind := KindInvalid + 1; int(kind) < len(nodeKindNames); kind++ {
+		m[kind.String()] = kind
+	}
+	return m
+}()
+
+// Deserialize reads back the json of a tree written by Serialize: the
+// nodes with their parents and the tokens with their kind, offsets and
+// category. FileContents and Uri aren't serialized, they are left empty.
+//
+// The json of a token doesn't tell which node wrapped it, so a token in a
+// node child is read back as a Missing, a SkippedNode or a TokenNode after
+// its category, even where the parser wrapped a missing or skipped token
+// in a TokenNode. A TokenNode without a token, like the name of a
+// closure, is written as null and read back as nil. The result is equal to
+// the serialized tree with Equal in EqualStructure mode.
+func Deserialize(data []byte) (*SourceFileNode, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var tree interface{}
+	if err := decoder.Decode(&tree); err != nil {
+		return nil, fmt.Errorf("ast: %v", err)
+	}
+	node, err := deserializeNode(tree, nil)
+	if err != nil {
+		return nil, fmt.Errorf("ast: %v", err)
+	}
+	sourceFile, ok := node.(*SourceFileNode)
+	if !ok {
+		return nil, fmt.Errorf("ast: expected a SourceFileNode, got %s", node.NodeKind())
+	}
+	return sourceFile, nil
+}
+
+// deserializeNode builds a node from its {"Kind": {children}} json, or a
+// TokenNode, a Missing or a SkippedNode from the json of a token.
+func deserializeNode(value interface{}, parent Node) (Node, error) {
+	object, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("expected a node, got %s", describe(value))
+	}
+	if _, ok := object["kind"]; ok {
+		token, err := deserializeToken(value)
+		if err != nil {
+			return nil, err
+		}
+		var node NodeWithToken
+		switch token.Cat {
+		case lexer.TokenCatMissing:
+			node = &Missing{}
+		case lexer.TokenCatSkipped:
+			node = &SkippedNode{}
+		default:
+			node = &TokenNode{}
+		}
+		node.SetToken(token)
+		node.SetParent(parent)
+		return node, nil
+	}
+	if len(object) != 1 {
+		return nil, fmt.Errorf("expected a node, got an object of %d keys", len(object))
+	}
+	// the only key is the kind
+	var name string
+	for name, value = range object {
+	}
+	kind, ok := nodeKindsByName[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown node %s", name)
+	}
+	fields, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%s: expected the children, got %s", name, describe(value))
+	}
+	node := newNode(kind)
+	node.SetParent(parent)
+	names := childNames[kind]
+	found := 0
+	for i, childName := range names {
+		value, ok := fields[childName]
+		if ok {
+			found++
+		}
+		if !ok || value == nil {
+			// omitted or absent
+			continue
+		}
+		var options childOption
+		if childOptions[kind] != nil {
+			options = childOptions[kind][i]
+		}
+		child, err := deserializeChild(value, childTypes[kind][i], options, node)
+		if err != nil {
+			return nil, fmt.Errorf("%s.%s: %v", name, childName, err)
+		}
+		if !node.(childSetter).setChild(i, child) {
+			return nil, fmt.Errorf("%s.%s: unexpected %T", name, childName, child)
+		}
+	}
+	if found < len(fields) {
+		for childName := range fields {
+			if !contains(names, childName) {
+				return nil, fmt.Errorf("%s: unknown child %s", name, childName)
+			}
+		}
+	}
+	return node, nil
+}
+
+func deserializeChild(value interface{}, t childType, options childOption, parent Node) (interface{}, error) {
+	switch t {
+	case childToken:
+		return deserializeToken(value)
+	case childTokens:
+		list, ok := value.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("expected a list of tokens, got %s", describe(value))
+		}
+		tokens := make([]*lexer.Token, len(list))
+		for i, item := range list {
+			token, err := deserializeToken(item)
+			if err != nil {
+				return nil, fmt.Errorf("%d: %v", i, err)
+			}
+			tokens[i] = token
+		}
+		return tokens, nil
+	case childNode:
+		return deserializeNode(value, parent)
+	case childNodes:
+		if _, ok := value.([]interface{}); !ok && options&childSingle != 0 {
+			// a list of one node written as the node
+			node, err := deserializeNode(value, parent)
+			if err != nil {
+				return nil, err
+			}
+			return []Node{node}, nil
+		}
+		return deserializeNodes(value, parent)
+	case childAny:
+		if _, ok := value.([]interface{}); ok {
+			return deserializeNodes(value, parent)
+		}
+		if object, ok := value.(map[string]interface{}); ok {
+			if _, ok := object["kind"]; ok {
+				return deserializeToken(value)
+			}
+		}
+		return deserializeNode(value, parent)
+	}
+	return nil, fmt.Errorf("unknown child type %d", t)
+}
+
+func deserializeNodes(value interface{}, parent Node) ([]Node, error) {
+	list, ok := value.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("expected a list of nodes, got %s", describe(value))
+	}
+	nodes := make([]Node, len(list))
+	for i, item := range list {
+		node, err := deserializeNode(item, parent)
+		if err != nil {
+			return nil, fmt.Errorf("%d: %v", i, err)
+		}
+		nodes[i] = node
+	}
+	return nodes, nil
+}
+
+func deserializeToken(value interface{}) (*lexer.Token, error) {
+	object, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("expected a token, got %s", describe(value))
+	}
+	name, _ := object["kind"].(string)
+	kind, ok := lexer.TokenKindByName(name)
+	if !ok {
+		return nil, fmt.Errorf("unknown token kind %q", name)
+	}
+	token := &lexer.Token{Kind: kind}
+	for _, offset := range []struct {
+		name  string
+		value *int
+	}{{"fullStart", &token.FullStart}, {"start", &token.Start}, {"length", &token.Length}} {
+		number, ok := object[offset.name].(json.Number)
+		if !ok {
+			return nil, fmt.Errorf("%s: expected the %s offset", name, offset.name)
+		}
+		n, err := number.Int64()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s: %v", name, offset.name, err)
+		}
+		*offset.value = int(n)
+	}
+	switch object["error"] {
+	case nil:
+	case "MissingToken":
+		token.Cat = lexer.TokenCatMissing
+	case "SkippedToken":
+		token.Cat = lexer.TokenCatSkipped
+	default:
+		return nil, fmt.Errorf("%s: unknown error %v", name, object["error"])
+	}
+	return token, nil
+}
+
+// describe returns the json type of a decoded value for the errors.
+func describe(value interface{}) string {
+	switch value.(type) {
+	case map[string]interface{}:
+		return "an object"
+	case []interface{}:
+		return "a list"
+	case string:
+		return "a string"
+	case json.Number:
+		return "a number"
+	case bool:
+		return "a boolean"
+	}
+	return "null"
+}
+
+func contains(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,209 @@
+package ast_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/emilioastarita/gphp/ast"
+	"github.com/emilioastarita/gphp/lexer"
+	"github.com/emilioastarita/gphp/parser"
+)
+
+// TestDeserialize reads back the tree of every parser case and writes it
+// again. The goldens that hold the php source, a note or a tree of the old
+// mstpp format, where tokens only have a textLength, must be rejected.
+func TestDeserialize(t *testing.T) {
+	goldens, _ := filepath.Glob("../parser/cases/*.php.tree")
+	if len(goldens) == 0 {
+		t.Fatal("no parser goldens found")
+	}
+	for _, golden := range goldens {
+		data, err := ioutil.ReadFile(golden)
+		if err != nil {
+			t.Fatal(err)
+		}
+		tree := json.Valid(data) && !bytes.Contains(data, []byte(`"textLength"`))
+		sourceFile, err := ast.Deserialize(data)
+		if !tree {
+			if err == nil {
+				t.Errorf("%s: expected an error, it isn't a tree", golden)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: %v", golden, err)
+			continue
+		}
+		output, _ := json.Marshal(ast.Serialize(sourceFile))
+		var expected, actual interface{}
+		json.Unmarshal(data, &expected)
+		json.Unmarshal(output, &actual)
+		if !reflect.DeepEqual(expected, actual) {
+			t.Errorf("%s: the deserialized tree is written as:\n%s", golden, output)
+		}
+		checkParents(t, golden, sourceFile)
+	}
+}
+
+// TestDeserializeParsed parses every case and compares the tree with the
+// one read back from its json, node by node and token by token.
+func TestDeserializeParsed(t *testing.T) {
+	sourceFiles, _ := filepath.Glob("../parser/cases/*.php")
+	for _, sourceFileName := range sourceFiles {
+		source, _ := ioutil.ReadFile(sourceFileName)
+		p := parser.Parser{}
+		sourceFile := p.ParseSourceFile(source, sourceFileName)
+		data, _ := json.Marshal(ast.Serialize(sourceFile))
+		deserialized, err := ast.Deserialize(data)
+		if err != nil {
+			t.Errorf("%s: %v", sourceFileName, err)
+			continue
+		}
+		compareDeserialized(t, sourceFileName, sourceFile, deserialized)
+	}
+}
+
+// compareDeserialized checks that a child read back by Deserialize has the
+// type and the tokens of the parsed one, but for the wrappers of tokens
+// that Deserialize documents it can't tell apart.
+func compareDeserialized(t *testing.T, name string, parsed, deserialized interface{}) {
+	if tokenNode, ok := parsed.(*ast.TokenNode); ok && tokenNode != nil {
+		switch {
+		case tokenNode.Token == nil:
+			parsed = nil
+		case tokenNode.Token.Cat == lexer.TokenCatMissing:
+			parsed = &ast.Missing{Token: tokenNode.Token}
+		case tokenNode.Token.Cat == lexer.TokenCatSkipped:
+			parsed = &ast.SkippedNode{Token: tokenNode.Token}
+		}
+	}
+	if isAbsent(parsed) || isAbsent(deserialized) {
+		if !isAbsent(parsed) || !isAbsent(deserialized) {
+			t.Errorf("%s: parsed %T, deserialized %T", name, parsed, deserialized)
+		}
+		return
+	}
+	if reflect.TypeOf(parsed) != reflect.TypeOf(deserialized) {
+		t.Errorf("%s: parsed %T, deserialized %T", name, parsed, deserialized)
+		return
+	}
+	switch parsed := parsed.(type) {
+	case *lexer.Token:
+		token := deserialized.(*lexer.Token)
+		if parsed.Kind != token.Kind || parsed.FullStart != token.FullStart || parsed.Start != token.Start ||
+			parsed.Length != token.Length || parsed.Cat != token.Cat {
+			t.Errorf("%s: parsed %+v, deserialized %+v", name, parsed, token)
+		}
+	case ast.Node:
+		childrenA, childrenB := parsed.Children(), deserialized.(ast.Node).Children()
+		for i := range childrenA {
+			compareDeserialized(t, name, childrenA[i], childrenB[i])
+		}
+	case []ast.Node:
+		nodes := deserialized.([]ast.Node)
+		if len(parsed) != len(nodes) {
+			t.Errorf("%s: parsed %d nodes, deserialized %d", name, len(parsed), len(nodes))
+			return
+		}
+		for i := range parsed {
+			compareDeserialized(t, name, parsed[i], nodes[i])
+		}
+	case []*lexer.Token:
+		tokens := deserialized.([]*lexer.Token)
+		if len(parsed) != len(tokens) {
+			t.Errorf("%s: parsed %d tokens, deserialized %d", name, len(parsed), len(tokens))
+			return
+		}
+		for i := range parsed {
+			compareDeserialized(t, name, parsed[i], tokens[i])
+		}
+	}
+}
+
+func isAbsent(child interface{}) bool {
+	switch child := child.(type) {
+	case nil:
+		return true
+	case *lexer.Token:
+		return child == nil
+	case ast.Node:
+		return child.Children() == nil
+	case []ast.Node:
+		return len(child) == 0
+	case []*lexer.Token:
+		return len(child) == 0
+	}
+	return false
+}
+
+// checkParents checks that the child nodes of node have node as parent.
+func checkParents(t *testing.T, golden string, node ast.Node) {
+	var check func(child interface{})
+	check = func(child interface{}) {
+		switch child := child.(type) {
+		case ast.Node:
+			if child.Parent() != node {
+				t.Errorf("%s: %s child of %s has parent %v", golden, child.NodeKind(), node.NodeKind(), child.Parent())
+			}
+			checkParents(t, golden, child)
+		case []ast.Node:
+			for _, n := range child {
+				check(n)
+			}
+		}
+	}
+	for _, child := range node.Children() {
+		check(child)
+	}
+}
+
+func TestDeserializeTokens(t *testing.T) {
+	sourceFile, err := ast.Deserialize([]byte(`{"SourceFileNode": {
+		"statementList": [
+			{"kind": "Name", "fullStart": 0, "start": 0, "length": 1, "error": "SkippedToken"},
+			{"ExpressionStatement": {
+				"expression": {"kind": "Expression", "fullStart": 1, "start": 1, "length": 0, "error": "MissingToken"},
+				"semicolon": {"kind": "SemicolonToken", "fullStart": 1, "start": 2, "length": 2}
+			}}
+		],
+		"endOfFileToken": {"kind": "EndOfFileToken", "fullStart": 3, "start": 3, "length": 0}
+	}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	skipped, ok := sourceFile.StatementList[0].(*ast.SkippedNode)
+	if !ok || skipped.Token.Kind != lexer.Name || skipped.Token.Cat != lexer.TokenCatSkipped {
+		t.Errorf("expected a skipped name, got %#v", sourceFile.StatementList[0])
+	}
+	statement := sourceFile.StatementList[1].(*ast.ExpressionStatement)
+	missing, ok := statement.Expression[0].(*ast.Missing)
+	if !ok || missing.Token.Kind != lexer.Expression || missing.Token.Cat != lexer.TokenCatMissing {
+		t.Errorf("expected a missing expression, got %#v", statement.Expression)
+	}
+	if semicolon := statement.Semicolon; semicolon.Kind != lexer.SemicolonToken || semicolon.FullStart != 1 || semicolon.Start != 2 || semicolon.Length != 2 {
+		t.Errorf("unexpected semicolon %+v", semicolon)
+	}
+}
+
+func TestDeserializeErrors(t *testing.T) {
+	cases := map[string]string{
+		`[]`:                     "expected a node, got a list",
+		`{"Foo": {}}`:            "unknown node Foo",
+		`{"EchoExpression": {}}`: "expected a SourceFileNode, got EchoExpression",
+		`{"SourceFileNode": {"statementList": [{"EchoExpression": {"echoKeyword": []}}]}}`: "SourceFileNode.statementList: 0: EchoExpression.echoKeyword: expected a token, got a list",
+		`{"SourceFileNode": {"endOfFileToken": {"kind": "Foo"}}}`:                          "unknown token kind",
+		`{"SourceFileNode": {"foo": null}}`:                                                "unknown child foo",
+		`{"SourceFileNode": {"statementList": {"EndOfFileToken": 1}}}`:                     "expected a list of nodes",
+	}
+	for data, expected := range cases {
+		_, err := ast.Deserialize([]byte(data))
+		if err == nil || !strings.Contains(err.Error(), expected) {
+			t.Errorf("%s: expected an error containing %q, got %v", data, expected, err)
+		}
+	}
+}
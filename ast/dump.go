@@ -0,0 +1,260 @@
+package ast
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/emilioastarita/gphp/lexer"
+)
+
+// maxDumpText is the number of characters of a token shown by the dumps,
+// an inline html can be a whole page.
+const maxDumpText = 40
+
+// dumpNode is a node or a token of a tree ready to be written by
+// FprintTree, FprintSexp and FprintDot. The children of a node are sorted
+// by their position in the source, not in the order of their fields, and
+// a list is flattened in its parent with the name of the field.
+type dumpNode struct {
+	name     string
+	kind     string
+	token    *lexer.Token
+	text     string
+	start    int
+	end      int
+	children []*dumpNode
+}
+
+func newDump(name string, child interface{}, source []byte) *dumpNode {
+	switch child := child.(type) {
+	case *lexer.Token:
+		if child == nil {
+			return nil
+		}
+		d := &dumpNode{name: name, kind: child.Kind.String(), token: child, start: child.Start, end: child.FullStart + child.Length}
+		if child.Cat == lexer.TokenCatSynthetic {
+			// a synthetic token has no position
+			d.start, d.end = -1, -1
+			d.text = child.Text(nil)
+		} else if child.Cat != lexer.TokenCatMissing && (child.HasSource() || d.end <= len(source)) {
+			d.text = child.Text(source)
+		}
+		return d
+	case *TokenNode:
+		if child == nil {
+			return nil
+		}
+		return newDump(name, child.Token, source)
+	case *SkippedNode:
+		if child == nil {
+			return nil
+		}
+		return newDump(name, child.Token, source)
+	case *Missing:
+		if child == nil {
+			return nil
+		}
+		return newDump(name, child.Token, source)
+	case Node:
+		children := child.Children()
+		if children == nil {
+			return nil
+		}
+		d := &dumpNode{name: name, kind: child.NodeKind().String(), start: -1, end: -1}
+		names := child.ChildNames()
+		for i, c := range children {
+			d.add(names[i], c, source)
+		}
+		sort.SliceStable(d.children, func(i, j int) bool {
+			return d.children[i].start < d.children[j].start
+		})
+		for _, c := range d.children {
+			if c.start < 0 {
+				continue
+			}
+			if d.start < 0 {
+				d.start = c.start
+			}
+			if c.end > d.end {
+				d.end = c.end
+			}
+		}
+		return d
+	}
+	return nil
+}
+
+func (d *dumpNode) add(name string, child interface{}, source []byte) {
+	switch child := child.(type) {
+	case []Node:
+		for _, node := range child {
+			d.add(name, node, source)
+		}
+	case []*lexer.Token:
+		for _, token := range child {
+			d.add(name, token, source)
+		}
+	default:
+		if c := newDump(name, child, source); c != nil {
+			d.children = append(d.children, c)
+		}
+	}
+}
+
+func (d *dumpNode) missing() bool {
+	return d.token != nil && d.token.Cat == lexer.TokenCatMissing
+}
+
+func (d *dumpNode) skipped() bool {
+	return d.token != nil && d.token.Cat == lexer.TokenCatSkipped
+}
+
+// quotedText returns the text of a token as a go string, cut after
+// maxDumpText characters.
+func (d *dumpNode) quotedText() string {
+	text := d.text
+	if utf8.RuneCountInString(text) > maxDumpText {
+		runes := []rune(text)
+		return strconv.Quote(string(runes[:maxDumpText])) + "..."
+	}
+	return strconv.Quote(text)
+}
+
+// label is the line of the node in FprintTree, the kind, the text of a
+// token and the range of bytes without the leading trivia.
+func (d *dumpNode) label() string {
+	var b strings.Builder
+	if d.name != "" {
+		b.WriteString(d.name + ": ")
+	}
+	b.WriteString(d.kind)
+	if d.token != nil && !d.missing() {
+		b.WriteString(" " + d.quotedText())
+	}
+	if d.start >= 0 {
+		fmt.Fprintf(&b, " [%d,%d)", d.start, d.end)
+	}
+	if d.missing() {
+		b.WriteString(" <<MISSING>>")
+	}
+	if d.skipped() {
+		b.WriteString(" <<SKIPPED>>")
+	}
+	return b.String()
+}
+
+// FprintTree writes a node as an ascii tree, a line for every node and
+// token with the name of its field, its kind, its text and its range of
+// bytes. The missing and skipped tokens are marked with <<MISSING>> and
+// <<SKIPPED>>. source is the text the node was parsed from.
+func FprintTree(w io.Writer, node Node, source []byte) error {
+	b := bufio.NewWriter(w)
+	if d := newDump("", node, source); d != nil {
+		b.WriteString(d.label() + "\n")
+		d.writeTree(b, "")
+	}
+	return b.Flush()
+}
+
+func (d *dumpNode) writeTree(b *bufio.Writer, indent string) {
+	for i, c := range d.children {
+		branch, next := "|-- ", "|   "
+		if i == len(d.children)-1 {
+			branch, next = "`-- ", "    "
+		}
+		b.WriteString(indent + branch + c.label() + "\n")
+		c.writeTree(b, indent+next)
+	}
+}
+
+// FprintSexp writes a node as an s-expression, (Kind children...) with
+// the tokens as their quoted text, a missing token as (MISSING Kind) and
+// a skipped one as (SKIPPED "text"). The statements of a SourceFileNode
+// are written on their own lines.
+func FprintSexp(w io.Writer, node Node, source []byte) error {
+	b := bufio.NewWriter(w)
+	if d := newDump("", node, source); d != nil {
+		separator := " "
+		if d.kind == KindSourceFileNode.String() {
+			separator = "\n  "
+		}
+		d.writeSexp(b, separator)
+		b.WriteString("\n")
+	}
+	return b.Flush()
+}
+
+func (d *dumpNode) writeSexp(b *bufio.Writer, separator string) {
+	switch {
+	case d.missing():
+		b.WriteString("(MISSING " + d.kind + ")")
+	case d.skipped():
+		b.WriteString("(SKIPPED " + d.quotedText() + ")")
+	case d.token != nil:
+		b.WriteString(d.quotedText())
+	default:
+		b.WriteString("(" + d.kind)
+		for _, c := range d.children {
+			b.WriteString(separator)
+			c.writeSexp(b, " ")
+		}
+		b.WriteString(")")
+	}
+}
+
+// FprintDot writes a node as a Graphviz digraph, the edges labeled with
+// the names of the fields. The missing tokens are red and the skipped
+// ones orange.
+func FprintDot(w io.Writer, node Node, source []byte) error {
+	b := bufio.NewWriter(w)
+	b.WriteString("digraph ast {\n")
+	b.WriteString("\tnode [shape=box, fontname=\"monospace\"];\n")
+	if d := newDump("", node, source); d != nil {
+		id := 0
+		d.writeDot(b, &id)
+	}
+	b.WriteString("}\n")
+	return b.Flush()
+}
+
+func (d *dumpNode) writeDot(b *bufio.Writer, id *int) {
+	self := *id
+	label := d.kind
+	if d.token != nil && !d.missing() {
+		label += "\n" + d.quotedText()
+	}
+	if d.start >= 0 {
+		label += fmt.Sprintf("\n[%d,%d)", d.start, d.end)
+	}
+	style := ""
+	switch {
+	case d.missing():
+		label += "\nmissing"
+		style = `, style="filled,dashed", color=red, fillcolor="#ffd0d0"`
+	case d.skipped():
+		label += "\nskipped"
+		style = `, style=filled, color=orange, fillcolor="#ffe8c0"`
+	case d.token != nil:
+		style = `, shape=plaintext`
+	}
+	fmt.Fprintf(b, "\tn%d [label=%s%s];\n", self, dotString(label), style)
+	for _, c := range d.children {
+		*id++
+		fmt.Fprintf(b, "\tn%d -> n%d [label=%s];\n", self, *id, dotString(c.name))
+		c.writeDot(b, id)
+	}
+}
+
+// dotString quotes a label of the dot language, its line feeds are
+// written as \n.
+func dotString(s string) string {
+	s = strings.Replace(s, `\`, `\\`, -1)
+	s = strings.Replace(s, `"`, `\"`, -1)
+	s = strings.Replace(s, "\n", `\n`, -1)
+	return `"` + s + `"`
+}
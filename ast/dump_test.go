@@ -0,0 +1,101 @@
+package ast_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/emilioastarita/gphp/ast"
+	"github.com/emilioastarita/gphp/parser"
+)
+
+const dumpSource = "<?php\nf($a, ]);\n"
+
+func TestFprintTree(t *testing.T) {
+	p := parser.Parser{}
+	sourceFile := p.ParseSourceFile([]byte(dumpSource), "")
+	expected := strings.Join([]string{
+		"SourceFileNode [0,16)",
+		"|-- statementList: InlineHtml [0,6)",
+		"|   `-- scriptSectionStartTag: ScriptSectionStartTag \"<?php\\n\" [0,6)",
+		"|-- statementList: ExpressionStatement [6,11)",
+		"|   |-- expression: CallExpression [6,11)",
+		"|   |   |-- callableExpression: QualifiedName [6,7)",
+		"|   |   |   `-- nameParts: Name \"f\" [6,7)",
+		"|   |   |-- openParen: OpenParenToken \"(\" [7,8)",
+		"|   |   |-- argumentExpressionList: ArgumentExpressionList [8,11)",
+		"|   |   |   |-- children: ArgumentExpression [8,10)",
+		"|   |   |   |   `-- expression: Variable [8,10)",
+		"|   |   |   |       `-- name: VariableName \"$a\" [8,10)",
+		"|   |   |   `-- children: CommaToken \",\" [10,11)",
+		"|   |   `-- closeParen: CloseParenToken [11,11) <<MISSING>>",
+		"|   `-- semicolon: SemicolonToken [11,11) <<MISSING>>",
+		"|-- statementList: CloseBracketToken \"]\" [12,13) <<SKIPPED>>",
+		"|-- statementList: CloseParenToken \")\" [13,14) <<SKIPPED>>",
+		"|-- statementList: EmptyStatement [14,15)",
+		"|   `-- semicolon: SemicolonToken \";\" [14,15)",
+		"`-- endOfFileToken: EndOfFileToken \"\" [16,16)",
+		"",
+	}, "\n")
+	var buf bytes.Buffer
+	ast.FprintTree(&buf, sourceFile, sourceFile.FileContents)
+	if buf.String() != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, buf.String())
+	}
+}
+
+func TestFprintSexp(t *testing.T) {
+	p := parser.Parser{}
+	sourceFile := p.ParseSourceFile([]byte(dumpSource), "")
+	expected := strings.Join([]string{
+		"(SourceFileNode",
+		"  (InlineHtml \"<?php\\n\")",
+		"  (ExpressionStatement (CallExpression (QualifiedName \"f\") \"(\" (ArgumentExpressionList (ArgumentExpression (Variable \"$a\")) \",\") (MISSING CloseParenToken)) (MISSING SemicolonToken))",
+		"  (SKIPPED \"]\")",
+		"  (SKIPPED \")\")",
+		"  (EmptyStatement \";\")",
+		"  \"\")",
+		"",
+	}, "\n")
+	var buf bytes.Buffer
+	ast.FprintSexp(&buf, sourceFile, sourceFile.FileContents)
+	if buf.String() != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, buf.String())
+	}
+}
+
+// TestFprintDot writes the parser cases as dot and checks every node has
+// a label and every edge a node.
+func TestFprintDot(t *testing.T) {
+	sourceFiles, _ := filepath.Glob("../parser/cases/*.php")
+	for _, sourceFileName := range sourceFiles {
+		data, _ := ioutil.ReadFile(sourceFileName)
+		p := parser.Parser{}
+		sourceFile := p.ParseSourceFile(data, "")
+		var buf bytes.Buffer
+		if err := ast.FprintDot(&buf, sourceFile, data); err != nil {
+			t.Fatal(err)
+		}
+		lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+		if lines[0] != "digraph ast {" || lines[len(lines)-1] != "}" {
+			t.Errorf("%s: not a digraph:\n%s", sourceFileName, buf.String())
+			continue
+		}
+		nodes, edges := 0, 0
+		for _, line := range lines[2 : len(lines)-1] {
+			switch {
+			case strings.Contains(line, " -> "):
+				edges++
+			case strings.HasPrefix(line, "\tn") && strings.Contains(line, " [label=\""):
+				nodes++
+			default:
+				t.Errorf("%s: unexpected line %q", sourceFileName, line)
+			}
+		}
+		if edges != nodes-1 {
+			t.Errorf("%s: expected a tree, got %d nodes and %d edges", sourceFileName, nodes, edges)
+		}
+	}
+}
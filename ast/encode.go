@@ -0,0 +1,377 @@
+package ast
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+
+	"github.com/emilioastarita/gphp/lexer"
+)
+
+// The binary encoding of a tree is
+//
+//	header  "GPHP", uvarint version, uint32 schema checksum
+//	tokens  uvarint count, then for every token in the order of the tree
+//	        uvarint kind<<2 | category
+//	        varint  full start - end of the previous token
+//	        uvarint start - full start
+//	        uvarint length
+//	tree    the SourceFileNode
+//
+// A node is its uvarint NodeKind, 0 for nil, followed by a tag for each of
+// its children: 0 nil, 1 token (the next one of the tokens), 2 node, 3
+// list of nodes and 4 list of tokens, the lists prefixed by their uvarint
+// length. The kinds are numbers, the checksum of the names of the kinds
+// and of the children rejects the encodings of other versions of the ast.
+// FileContents and Uri aren't encoded.
+
+// encodingVersion changes with the layout of the encoding.
+const encodingVersion = 1
+
+var encodingMagic = []byte("GPHP")
+
+const (
+	tagNil byte = iota
+	tagToken
+	tagNode
+	tagNodes
+	tagTokens
+)
+
+// schemaChecksum identifies the kinds and children of the nodes and the
+// kinds of the tokens.
+var schemaChecksum = func() uint32 {
+	h := crc32.NewIEEE()
+	for kind, name := range nodeKindNames {
+		fmt.Fprintf(h, "%s(%v)\n", name, childNames[kind])
+	}
+	for kind := lexer.TokenKind(0); ; kind++ {
+		name := kind.String()
+		if _, ok := lexer.TokenKindByName(name); !ok {
+			break
+		}
+		fmt.Fprintln(h, name)
+	}
+	return h.Sum32()
+}()
+
+type encoder struct {
+	tokens     []byte
+	tree       []byte
+	tokenCount int
+	end        int
+	scratch    [binary.MaxVarintLen64]byte
+}
+
+// Encode writes the binary encoding of a tree, a smaller and faster to
+// read alternative to Serialize for caches.
+func Encode(w io.Writer, root *SourceFileNode) error {
+	e := &encoder{}
+	if err := e.node(root); err != nil {
+		return err
+	}
+	header := append([]byte{}, encodingMagic...)
+	header = appendUvarint(header, encodingVersion)
+	header = append(header, 0, 0, 0, 0)
+	binary.LittleEndian.PutUint32(header[len(header)-4:], schemaChecksum)
+	header = appendUvarint(header, uint64(e.tokenCount))
+	for _, b := range [][]byte{header, e.tokens, e.tree} {
+		if _, err := w.Write(b); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *encoder) node(node Node) error {
+	var children []interface{}
+	if node != nil {
+		children = node.Children()
+	}
+	if children == nil {
+		e.tree = appendUvarint(e.tree, uint64(KindInvalid))
+		return nil
+	}
+	e.tree = appendUvarint(e.tree, uint64(node.NodeKind()))
+	for _, child := range children {
+		if err := e.child(child); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *encoder) child(child interface{}) error {
+	switch child := child.(type) {
+	case nil:
+		e.tree = append(e.tree, tagNil)
+	case *lexer.Token:
+		if child == nil {
+			e.tree = append(e.tree, tagNil)
+			return nil
+		}
+		e.tree = append(e.tree, tagToken)
+		return e.token(child)
+	case Node:
+		e.tree = append(e.tree, tagNode)
+		return e.node(child)
+	case []Node:
+		if child == nil {
+			e.tree = append(e.tree, tagNil)
+			return nil
+		}
+		e.tree = append(e.tree, tagNodes)
+		e.tree = appendUvarint(e.tree, uint64(len(child)))
+		for _, node := range child {
+			if err := e.node(node); err != nil {
+				return err
+			}
+		}
+	case []*lexer.Token:
+		if child == nil {
+			e.tree = append(e.tree, tagNil)
+			return nil
+		}
+		e.tree = append(e.tree, tagTokens)
+		e.tree = appendUvarint(e.tree, uint64(len(child)))
+		for _, token := range child {
+			if token == nil {
+				return errors.New("ast: nil token in a list")
+			}
+			if err := e.token(token); err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("ast: unexpected child %T", child)
+	}
+	return nil
+}
+
+func (e *encoder) token(token *lexer.Token) error {
+	if token.Cat == lexer.TokenCatSynthetic {
+		// its text isn't in the source
+		return errors.New("ast: can't encode a synthetic token")
+	}
+	e.tokenCount++
+	e.tokens = appendUvarint(e.tokens, uint64(token.Kind)<<2|uint64(token.Cat))
+	n := binary.PutVarint(e.scratch[:], int64(token.FullStart-e.end))
+	e.tokens = append(e.tokens, e.scratch[:n]...)
+	e.tokens = appendUvarint(e.tokens, uint64(token.Start-token.FullStart))
+	e.tokens = appendUvarint(e.tokens, uint64(token.Length))
+	e.end = token.FullStart + token.Length
+	return nil
+}
+
+func appendUvarint(b []byte, v uint64) []byte {
+	var scratch [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(scratch[:], v)
+	return append(b, scratch[:n]...)
+}
+
+type decoder struct {
+	r      io.ByteReader
+	tokens []*lexer.Token
+	next   int
+}
+
+// Decode reads a tree written by Encode.
+func Decode(r io.Reader) (*SourceFileNode, error) {
+	byteReader, ok := r.(io.ByteReader)
+	if !ok {
+		byteReader = bufio.NewReader(r)
+	}
+	d := &decoder{r: byteReader}
+	root, err := d.decode()
+	if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+	if err != nil {
+		return nil, fmt.Errorf("ast: %v", err)
+	}
+	return root, nil
+}
+
+func (d *decoder) decode() (*SourceFileNode, error) {
+	magic := make([]byte, len(encodingMagic))
+	for i := range magic {
+		b, err := d.r.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+		magic[i] = b
+	}
+	if !bytes.Equal(magic, encodingMagic) {
+		return nil, errors.New("not an encoded tree")
+	}
+	version, err := binary.ReadUvarint(d.r)
+	if err != nil {
+		return nil, err
+	}
+	if version != encodingVersion {
+		return nil, fmt.Errorf("unsupported encoding version %d", version)
+	}
+	var checksum [4]byte
+	for i := range checksum {
+		if checksum[i], err = d.r.ReadByte(); err != nil {
+			return nil, err
+		}
+	}
+	if binary.LittleEndian.Uint32(checksum[:]) != schemaChecksum {
+		return nil, errors.New("encoded with another version of the ast")
+	}
+	if err := d.readTokens(); err != nil {
+		return nil, err
+	}
+	node, err := d.node(nil)
+	if err != nil {
+		return nil, err
+	}
+	root, ok := node.(*SourceFileNode)
+	if !ok {
+		return nil, errors.New("the root isn't a SourceFileNode")
+	}
+	if d.next != len(d.tokens) {
+		return nil, fmt.Errorf("%d tokens aren't in the tree", len(d.tokens)-d.next)
+	}
+	return root, nil
+}
+
+func (d *decoder) readTokens() error {
+	count, err := binary.ReadUvarint(d.r)
+	if err != nil {
+		return err
+	}
+	// the count isn't trusted for the allocation
+	d.tokens = make([]*lexer.Token, 0, minInt(count, 1<<16))
+	end := 0
+	for i := uint64(0); i < count; i++ {
+		kindAndCat, err := binary.ReadUvarint(d.r)
+		if err != nil {
+			return err
+		}
+		fullStart, err := binary.ReadVarint(d.r)
+		if err != nil {
+			return err
+		}
+		start, err := binary.ReadUvarint(d.r)
+		if err != nil {
+			return err
+		}
+		length, err := binary.ReadUvarint(d.r)
+		if err != nil {
+			return err
+		}
+		token := &lexer.Token{
+			Kind:      lexer.TokenKind(kindAndCat >> 2),
+			Cat:       lexer.TokenCategory(kindAndCat & 3),
+			FullStart: end + int(fullStart),
+			Length:    int(length),
+		}
+		if token.Cat == lexer.TokenCatSynthetic {
+			return errors.New("ast: synthetic token in the encoding")
+		}
+		token.Start = token.FullStart + int(start)
+		end = token.FullStart + token.Length
+		d.tokens = append(d.tokens, token)
+	}
+	return nil
+}
+
+func (d *decoder) token() (*lexer.Token, error) {
+	if d.next >= len(d.tokens) {
+		return nil, errors.New("more tokens in the tree than encoded")
+	}
+	token := d.tokens[d.next]
+	d.next++
+	return token, nil
+}
+
+func (d *decoder) node(parent Node) (Node, error) {
+	k, err := binary.ReadUvarint(d.r)
+	if err != nil {
+		return nil, err
+	}
+	if k == uint64(KindInvalid) {
+		return nil, nil
+	}
+	if k >= uint64(len(nodeKindNames)) {
+		return nil, fmt.Errorf("unknown node kind %d", k)
+	}
+	kind := NodeKind(k)
+	node := newNode(kind)
+	node.SetParent(parent)
+	for i := range childNames[kind] {
+		child, err := d.child(node)
+		if err != nil {
+			return nil, fmt.Errorf("%s.%s: %v", kind, childNames[kind][i], err)
+		}
+		if child == nil {
+			continue
+		}
+		if !node.(childSetter).setChild(i, child) {
+			return nil, fmt.Errorf("%s.%s: unexpected %T", kind, childNames[kind][i], child)
+		}
+	}
+	return node, nil
+}
+
+func (d *decoder) child(parent Node) (interface{}, error) {
+	tag, err := d.r.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+	switch tag {
+	case tagNil:
+		return nil, nil
+	case tagToken:
+		return d.token()
+	case tagNode:
+		node, err := d.node(parent)
+		if node == nil || err != nil {
+			// a nil Node, not a nil *T in a Node
+			return nil, err
+		}
+		return node, nil
+	case tagNodes:
+		count, err := binary.ReadUvarint(d.r)
+		if err != nil {
+			return nil, err
+		}
+		nodes := make([]Node, 0, minInt(count, 1<<10))
+		for i := uint64(0); i < count; i++ {
+			node, err := d.node(parent)
+			if err != nil {
+				return nil, err
+			}
+			nodes = append(nodes, node)
+		}
+		return nodes, nil
+	case tagTokens:
+		count, err := binary.ReadUvarint(d.r)
+		if err != nil {
+			return nil, err
+		}
+		tokens := make([]*lexer.Token, 0, minInt(count, 1<<10))
+		for i := uint64(0); i < count; i++ {
+			token, err := d.token()
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, token)
+		}
+		return tokens, nil
+	}
+	return nil, fmt.Errorf("unknown tag %d", tag)
+}
+
+func minInt(count uint64, max int) int {
+	if count < uint64(max) {
+		return int(count)
+	}
+	return max
+}
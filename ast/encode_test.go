@@ -0,0 +1,135 @@
+package ast_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/emilioastarita/gphp/ast"
+	"github.com/emilioastarita/gphp/lexer"
+	"github.com/emilioastarita/gphp/parser"
+)
+
+// TestEncode encodes and decodes the trees of the parser cases.
+func TestEncode(t *testing.T) {
+	sourceFiles, _ := filepath.Glob("../parser/cases/*.php")
+	sourceFiles = append(sourceFiles, "../lexer/cases/complex.php")
+	jsonSize, encodedSize := 0, 0
+	for _, sourceFileName := range sourceFiles {
+		data, _ := ioutil.ReadFile(sourceFileName)
+		p := parser.Parser{}
+		sourceFile := p.ParseSourceFile(data, "")
+
+		var buf bytes.Buffer
+		if err := ast.Encode(&buf, sourceFile); err != nil {
+			t.Fatalf("%s: %v", sourceFileName, err)
+		}
+		encodedSize += buf.Len()
+		decoded, err := ast.Decode(&buf)
+		if err != nil {
+			t.Errorf("%s: %v", sourceFileName, err)
+			continue
+		}
+
+		expected, _ := json.Marshal(ast.Serialize(sourceFile))
+		jsonSize += len(expected)
+		actual, _ := json.Marshal(ast.Serialize(decoded))
+		if !bytes.Equal(actual, expected) {
+			t.Errorf("%s: the decoded tree is written as:\n%s", sourceFileName, actual)
+		}
+		if kinds(sourceFile) != kinds(decoded) {
+			t.Errorf("%s: the decoded nodes differ:\n%s\n%s", sourceFileName, kinds(decoded), kinds(sourceFile))
+		}
+		checkParents(t, sourceFileName, decoded)
+	}
+	if encodedSize*10 > jsonSize {
+		t.Errorf("expected the encoding to be a tenth of the json at most, got %d bytes for %d", encodedSize, jsonSize)
+	}
+}
+
+// kinds returns the kinds of the nodes of a tree and their tokens in the
+// order of the tree, the json of Serialize doesn't tell whether a token
+// is in a TokenNode, a Missing or a SkippedNode.
+func kinds(node ast.Node) string {
+	var b strings.Builder
+	var walk func(child interface{})
+	walk = func(child interface{}) {
+		switch child := child.(type) {
+		case *lexer.Token:
+			b.WriteString(child.Kind.String() + " ")
+		case ast.Node:
+			b.WriteString(child.NodeKind().String() + "(")
+			for _, c := range child.Children() {
+				walk(c)
+			}
+			b.WriteString(") ")
+		case []ast.Node:
+			for _, n := range child {
+				walk(n)
+			}
+		case []*lexer.Token:
+			for _, token := range child {
+				walk(token)
+			}
+		}
+	}
+	walk(node)
+	return b.String()
+}
+
+func TestDecodeErrors(t *testing.T) {
+	p := parser.Parser{}
+	sourceFile := p.ParseSourceFile([]byte("<?php class A { function b($c) { return [1, $c]; } }"), "")
+	var buf bytes.Buffer
+	ast.Encode(&buf, sourceFile)
+	encoded := buf.Bytes()
+
+	// every truncation is an error
+	for i := 0; i < len(encoded); i++ {
+		if _, err := ast.Decode(bytes.NewReader(encoded[:i])); err == nil {
+			t.Errorf("expected an error decoding %d of %d bytes", i, len(encoded))
+		}
+	}
+	// corruptions don't panic
+	for i := 0; i < len(encoded); i++ {
+		corrupted := append([]byte{}, encoded...)
+		corrupted[i] ^= 0xff
+		ast.Decode(bytes.NewReader(corrupted))
+	}
+
+	version := append([]byte{}, encoded...)
+	version[4] = 2
+	if _, err := ast.Decode(bytes.NewReader(version)); err == nil || !strings.Contains(err.Error(), "version") {
+		t.Errorf("expected a version error, got %v", err)
+	}
+	if _, err := ast.Decode(strings.NewReader(`{"SourceFileNode": {}}`)); err == nil {
+		t.Errorf("expected an error decoding json")
+	}
+}
+
+func BenchmarkEncode(b *testing.B) {
+	data, _ := ioutil.ReadFile("../lexer/cases/complex.php")
+	p := parser.Parser{}
+	sourceFile := p.ParseSourceFile(data, "")
+	b.ReportAllocs()
+
+	for n := 0; n < b.N; n++ {
+		ast.Encode(ioutil.Discard, sourceFile)
+	}
+}
+
+func BenchmarkDecode(b *testing.B) {
+	data, _ := ioutil.ReadFile("../lexer/cases/complex.php")
+	p := parser.Parser{}
+	sourceFile := p.ParseSourceFile(data, "")
+	var buf bytes.Buffer
+	ast.Encode(&buf, sourceFile)
+	b.ReportAllocs()
+
+	for n := 0; n < b.N; n++ {
+		ast.Decode(bytes.NewReader(buf.Bytes()))
+	}
+}
@@ -0,0 +1,248 @@
+//go:build ignore
+// +build ignore
+
+// gen_children writes children_gen.go: the NodeKind of every node of the
+// package, the Children and ChildNames methods that walk a node without
+// reflection and the setters used by Deserialize. The children of a node
+// are its exported fields, in order, with the fields of the structs
+// embedded with the -flat tag in place of them.
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"log"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// files declaring the nodes, the kinds follow their order
+var files = []string{"ast.go", "statements.go", "functions.go", "delimited_list.go", "token_nodes.go"}
+
+type field struct {
+	goName    string
+	name      string
+	goType    string
+	single    bool
+	omitEmpty bool
+}
+
+type node struct {
+	name   string
+	fields []field
+}
+
+func main() {
+	fset := token.NewFileSet()
+	structs := map[string]*ast.StructType{}
+	var names []string
+	for _, file := range files {
+		f, err := parser.ParseFile(fset, file, nil, 0)
+		if err != nil {
+			log.Fatal(err)
+		}
+		for _, decl := range f.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok || gen.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range gen.Specs {
+				typeSpec := spec.(*ast.TypeSpec)
+				if st, ok := typeSpec.Type.(*ast.StructType); ok {
+					structs[typeSpec.Name.Name] = st
+					names = append(names, typeSpec.Name.Name)
+				}
+			}
+		}
+	}
+
+	var nodes []node
+	for _, name := range names {
+		if isNode(structs, name) {
+			nodes = append(nodes, node{name, fieldsOf(structs, name)})
+		}
+	}
+
+	out := &bytes.Buffer{}
+	fmt.Fprintf(out, "// Code generated by \"go run gen_children.go\"; DO NOT EDIT.\n\n")
+	fmt.Fprintf(out, "package ast\n\nimport \"github.com/emilioastarita/gphp/lexer\"\n\n")
+	fmt.Fprintf(out, "const (\n\tKindInvalid NodeKind = iota\n")
+	for _, n := range nodes {
+		fmt.Fprintf(out, "\tKind%s\n", n.name)
+	}
+	fmt.Fprintf(out, ")\n\nvar nodeKindNames = [...]string{\n\tKindInvalid: \"Invalid\",\n")
+	for _, n := range nodes {
+		fmt.Fprintf(out, "\tKind%s: %q,\n", n.name, n.name)
+	}
+	fmt.Fprintf(out, "}\n\nvar childNames = [...][]string{\n")
+	for _, n := range nodes {
+		quoted := make([]string, len(n.fields))
+		for i, f := range n.fields {
+			quoted[i] = strconv.Quote(f.name)
+		}
+		fmt.Fprintf(out, "\tKind%s: {%s},\n", n.name, strings.Join(quoted, ", "))
+	}
+	fmt.Fprintf(out, "}\n\nvar childOptions = [len(nodeKindNames)][]childOption{\n")
+	for _, n := range nodes {
+		options := make([]string, len(n.fields))
+		hasOptions := false
+		for i, f := range n.fields {
+			var set []string
+			if f.single {
+				set = append(set, "childSingle")
+			}
+			if f.omitEmpty {
+				set = append(set, "childOmitEmpty")
+			}
+			if len(set) == 0 {
+				options[i] = "0"
+				continue
+			}
+			options[i] = strings.Join(set, " | ")
+			hasOptions = true
+		}
+		if hasOptions {
+			fmt.Fprintf(out, "\tKind%s: {%s},\n", n.name, strings.Join(options, ", "))
+		}
+	}
+	fmt.Fprintf(out, "}\n\nvar childTypes = [...][]childType{\n")
+	for _, n := range nodes {
+		types := make([]string, len(n.fields))
+		for i, f := range n.fields {
+			types[i] = childTypeOf(f.goType)
+		}
+		fmt.Fprintf(out, "\tKind%s: {%s},\n", n.name, strings.Join(types, ", "))
+	}
+	fmt.Fprintf(out, "}\n\n// newNode returns an empty node of a kind, nil for KindInvalid.\n")
+	fmt.Fprintf(out, "func newNode(kind NodeKind) Node {\n\tswitch kind {\n")
+	for _, n := range nodes {
+		fmt.Fprintf(out, "\tcase Kind%s:\n\t\treturn &%s{}\n", n.name, n.name)
+	}
+	fmt.Fprintf(out, "\t}\n\treturn nil\n}\n")
+
+	for _, n := range nodes {
+		fmt.Fprintf(out, "\nfunc (n *%s) NodeKind() NodeKind {\n\treturn Kind%s\n}\n", n.name, n.name)
+		fmt.Fprintf(out, "\nfunc (n *%s) ChildNames() []string {\n\treturn childNames[Kind%s]\n}\n", n.name, n.name)
+		fmt.Fprintf(out, "\nfunc (n *%s) Children() []interface{} {\n\tif n == nil {\n\t\treturn nil\n\t}\n", n.name)
+		var nilPointers []int
+		fmt.Fprintf(out, "\tchildren := []interface{}{")
+		for i, f := range n.fields {
+			if i > 0 {
+				fmt.Fprintf(out, ", ")
+			}
+			fmt.Fprintf(out, "n.%s", f.goName)
+			if strings.HasPrefix(f.goType, "*") {
+				nilPointers = append(nilPointers, i)
+			}
+		}
+		fmt.Fprintf(out, "}\n")
+		// a nil pointer isn't a nil interface
+		for _, i := range nilPointers {
+			fmt.Fprintf(out, "\tif n.%s == nil {\n\t\tchildren[%d] = nil\n\t}\n", n.fields[i].goName, i)
+		}
+		fmt.Fprintf(out, "\treturn children\n}\n")
+		fmt.Fprintf(out, "\nfunc (n *%s) setChild(i int, child interface{}) (ok bool) {\n\tswitch i {\n", n.name)
+		for i, f := range n.fields {
+			fmt.Fprintf(out, "\tcase %d:\n\t\tn.%s, ok = child.(%s)\n", i, f.goName, f.goType)
+		}
+		fmt.Fprintf(out, "\t}\n\treturn ok\n}\n")
+	}
+
+	source, err := format.Source(out.Bytes())
+	if err != nil {
+		log.Fatalf("%v\n%s", err, out.Bytes())
+	}
+	if err := ioutil.WriteFile("children_gen.go", source, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// isNode reports whether a struct embeds CNode, itself or through the
+// structs it flattens.
+func isNode(structs map[string]*ast.StructType, name string) bool {
+	st, ok := structs[name]
+	if !ok || name == "CNode" {
+		return false
+	}
+	for _, f := range st.Fields.List {
+		if len(f.Names) > 0 {
+			continue
+		}
+		embedded := typeString(f.Type)
+		if embedded == "CNode" || tag(f) == "-flat" && isNode(structs, embedded) {
+			return true
+		}
+	}
+	return false
+}
+
+// fieldsOf returns the children of a struct.
+func fieldsOf(structs map[string]*ast.StructType, name string) []field {
+	var fields []field
+	for _, f := range structs[name].Fields.List {
+		t := tag(f)
+		if len(f.Names) == 0 {
+			if t == "-flat" {
+				fields = append(fields, fieldsOf(structs, typeString(f.Type))...)
+			}
+			continue
+		}
+		for _, fieldName := range f.Names {
+			if t == "-" || !fieldName.IsExported() {
+				continue
+			}
+			name := t
+			if name == "" || name[0] == '-' {
+				r := []rune(fieldName.Name)
+				r[0] = unicode.ToLower(r[0])
+				name = string(r)
+			}
+			fields = append(fields, field{
+				goName:    fieldName.Name,
+				name:      name,
+				goType:    typeString(f.Type),
+				single:    t == "-single",
+				omitEmpty: t == "-omitempty",
+			})
+		}
+	}
+	return fields
+}
+
+// childTypeOf returns the childType of the go type of a field.
+func childTypeOf(goType string) string {
+	switch goType {
+	case "*lexer.Token":
+		return "childToken"
+	case "[]*lexer.Token":
+		return "childTokens"
+	case "[]Node":
+		return "childNodes"
+	case "NodeOrNodeColl":
+		return "childAny"
+	}
+	// Node, the interfaces of nodes and the pointers to nodes
+	return "childNode"
+}
+
+// tag returns the serialize tag of a field.
+func tag(f *ast.Field) string {
+	if f.Tag == nil {
+		return ""
+	}
+	value, _ := strconv.Unquote(f.Tag.Value)
+	return reflect.StructTag(value).Get("serialize")
+}
+
+func typeString(expr ast.Expr) string {
+	b := &bytes.Buffer{}
+	format.Node(b, token.NewFileSet(), expr)
+	return b.String()
+}
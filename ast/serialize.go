@@ -3,193 +3,111 @@ package ast
 import (
 	"bytes"
 	"encoding/json"
+	"fmt"
+
 	"github.com/emilioastarita/gphp/lexer"
-	goast "go/ast"
-	"reflect"
-	"unicode"
 )
 
-type serializer struct {
-	ptrmap            map[interface{}]bool // *T -> line number
-	ignoredFields     []string
-	typeOfToken       reflect.Type
-	typeOfTokenNode   reflect.Type
-	typeOfSkippedNode reflect.Type
-	typeOfMissing     reflect.Type
-	tagName           string
-}
-
-func Serialize(x interface{}) interface{} {
-	s := serializer{
-		tagName:           "serialize",
-		ptrmap:            make(map[interface{}]bool),
-		typeOfToken:       reflect.TypeOf(lexer.Token{}),
-		typeOfTokenNode:   reflect.TypeOf(TokenNode{}),
-		typeOfSkippedNode: reflect.TypeOf(SkippedNode{}),
-		typeOfMissing:     reflect.TypeOf(Missing{}),
-	}
-	return s.serialize(reflect.ValueOf(x), false)
-}
-
-func (s *serializer) formatSubField(x reflect.StructField) string {
-	if tag := x.Tag.Get(s.tagName); tag != "" && tag[0] != '-' {
-		return tag
-	}
-	r := []rune(x.Name)
-	r[0] = unicode.ToLower(r[0])
-	return string(r)
-}
-
-func (s *serializer) isIgnoredField(x reflect.StructField) bool {
-	if tag := x.Tag.Get(s.tagName); tag == "-" {
-		return true
-	}
-	return false
-}
-
-func (s *serializer) isEmbedded(x reflect.StructField) bool {
-	if tag := x.Tag.Get(s.tagName); tag == "-flat" {
-		return true
-	}
-	return false
-}
-func (s *serializer) isSingleChildren(x reflect.StructField) bool {
-	if tag := x.Tag.Get(s.tagName); tag == "-single" {
-		return true
-	}
-	return false
-}
+// childOption changes how Serialize writes a child, it comes from the
+// serialize tag of the field.
+type childOption uint8
 
-func (s *serializer) serialize(x reflect.Value, singleChildren bool) interface{} {
+const (
+	// childSingle writes a list of one element as the element, -single
+	childSingle childOption = 1 << iota
+	// childOmitEmpty leaves a nil child out, -omitempty
+	childOmitEmpty
+)
 
-	if !goast.NotNilFilter("", x) {
+// Serialize returns a node, a token or a list of them as maps and slices
+// ready to be encoded to the json of mstpp. A node is written as
+// {"Kind": {"childName": child, ...}} and a token as its kind, offsets and
+// error. A nil value is written as nil. Serialize panics on other values,
+// e.g. a **SourceFileNode, which it used to read by reflection.
+func Serialize(x interface{}) interface{} {
+	switch x := x.(type) {
+	case nil:
 		return nil
-	}
-
-	typeName := x.Type().Name()
-
-	switch x.Kind() {
-	case reflect.Interface:
-		return s.serialize(x.Elem(), false)
-	case reflect.Map:
-
-		me := make(map[string]map[string]interface{})
-		me[typeName] = make(map[string]interface{})
-		if x.Len() > 0 {
-			for _, key := range x.MapKeys() {
-				me[typeName][key.String()] = s.serialize(x.MapIndex(key), false)
-			}
-		}
-		return me
-
-	case reflect.Ptr:
-		// type-checked ASTs may contain cycles - use ptrmap
-		// to keep track of objects that have been printed
-		// already and print the respective line number instead
-		ptr := x.Interface()
-		if _, exists := s.ptrmap[ptr]; exists {
+	case *lexer.Token:
+		if x == nil {
 			return nil
-		} else {
-			s.ptrmap[ptr] = exists
-			return s.serialize(x.Elem(), false)
 		}
-
-	case reflect.Array:
-		me := make([]interface{}, x.Len())
-
-		// some children nodes uses only one object
-		// instead an array
-		if x.Len() == 1 && singleChildren {
-			return s.serialize(x.Index(0), false)
+		return serializeToken(x)
+	case *TokenNode:
+		if x == nil {
+			return nil
 		}
-
-		if x.Len() > 0 {
-			for i, n := 0, x.Len(); i < n; i++ {
-				me[i] = s.serialize(x.Index(i), false)
-			}
+		return Serialize(x.Token)
+	case *SkippedNode:
+		if x == nil {
+			return nil
 		}
-		return me
-
-	case reflect.Slice:
-		if _, ok := x.Interface().([]byte); ok {
+		return Serialize(x.Token)
+	case *Missing:
+		if x == nil {
 			return nil
 		}
-
-		// some children nodes uses only one object
-		// instead an array
-		if x.Len() == 1 && singleChildren {
-			return s.serialize(x.Index(0), false)
+		return Serialize(x.Token)
+	case Node:
+		children := x.Children()
+		if children == nil {
+			return nil
 		}
-
-		me := make([]interface{}, x.Len())
-		if x.Len() > 0 {
-			for i, n := 0, x.Len(); i < n; i++ {
-				me[i] = s.serialize(x.Index(i), false)
+		kind := x.NodeKind()
+		names := x.ChildNames()
+		options := childOptions[kind]
+		fields := make(map[string]interface{}, len(children))
+		for i, child := range children {
+			var option childOption
+			if options != nil {
+				option = options[i]
 			}
-		}
-		return me
-
-	case reflect.Struct:
-		t := x.Type()
-
-		switch t {
-		case s.typeOfToken:
-			me := make(map[string]interface{})
-			me["kind"] = s.serialize(x.FieldByName("Kind"), false)
-			me["fullStart"] = s.serialize(x.FieldByName("FullStart"), false)
-			me["start"] = s.serialize(x.FieldByName("Start"), false)
-			me["length"] = s.serialize(x.FieldByName("Length"), false)
-			serializedCat := s.serialize(x.FieldByName("Cat"), false)
-			cat, _ := serializedCat.(lexer.TokenCategory)
-			switch cat {
-			case lexer.TokenCatMissing:
-				me["error"] = "MissingToken"
-			case lexer.TokenCatSkipped:
-				me["error"] = "SkippedToken"
+			if option&childOmitEmpty != 0 && child == nil {
+				continue
 			}
-			return me
-
-		case s.typeOfSkippedNode,
-			s.typeOfMissing,
-			s.typeOfTokenNode:
-			return s.serialize(x.FieldByName("Token"), false)
-
-		default:
-			me := make(map[string]map[string]interface{})
-			me[typeName] = make(map[string]interface{})
-			for i, n := 0, t.NumField(); i < n; i++ {
-				// exclude non-exported fields because their
-				// values cannot be accessed via reflection
-				if field := t.Field(i); goast.IsExported(field.Name) && !s.isIgnoredField(field) {
-					value := x.Field(i)
-					name := s.formatSubField(field)
-
-					if s.isEmbedded(field) {
-						embedded := s.serialize(value, false)
-						m, ok := embedded.(map[string]map[string]interface{})
-						if ok {
-							for k, v := range m[field.Name] {
-								me[typeName][k] = v
-							}
-						}
-					} else {
-						me[typeName][name] = s.serialize(value, s.isSingleChildren(field))
-					}
-
-				}
+			if nodes, ok := child.([]Node); ok && option&childSingle != 0 && len(nodes) == 1 {
+				fields[names[i]] = Serialize(nodes[0])
+				continue
 			}
-			return me
+			fields[names[i]] = Serialize(child)
+		}
+		return map[string]map[string]interface{}{kind.String(): fields}
+	case []Node:
+		if x == nil {
+			return nil
+		}
+		me := make([]interface{}, len(x))
+		for i, node := range x {
+			me[i] = Serialize(node)
 		}
-	default:
-		v := x.Interface()
-		switch v := v.(type) {
-		case lexer.TokenKind:
-			return v.String()
+		return me
+	case []*lexer.Token:
+		if x == nil {
+			return nil
 		}
-		return v
+		me := make([]interface{}, len(x))
+		for i, token := range x {
+			me[i] = Serialize(token)
+		}
+		return me
 	}
+	panic(fmt.Sprintf("ast: can't serialize a %T", x))
+}
 
-	return nil
+func serializeToken(token *lexer.Token) map[string]interface{} {
+	me := map[string]interface{}{
+		"kind":      token.Kind.String(),
+		"fullStart": token.FullStart,
+		"start":     token.Start,
+		"length":    token.Length,
+	}
+	switch token.Cat {
+	case lexer.TokenCatMissing:
+		me["error"] = "MissingToken"
+	case lexer.TokenCatSkipped:
+		me["error"] = "SkippedToken"
+	}
+	return me
 }
 
 func PrettyPrintJSON(b []byte) ([]byte, error) {
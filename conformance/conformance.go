@@ -0,0 +1,234 @@
+// Package conformance checks the lexer and the parser against the golden
+// files of mstpp checked in next to the cases: the tokens of a case.php
+// are in case.php.tokens and its tree in case.php.tree. No php is needed
+// to check them, only to regenerate them.
+package conformance
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/emilioastarita/gphp/ast"
+	"github.com/emilioastarita/gphp/lexer"
+	"github.com/emilioastarita/gphp/parser"
+	diff "github.com/yudai/gojsondiff"
+	"github.com/yudai/gojsondiff/formatter"
+)
+
+// Kind is the kind of a golden file.
+type Kind int
+
+const (
+	// Tokens goldens hold the tokens of the lexer.
+	Tokens Kind = iota
+	// Tree goldens hold the serialized tree of the parser.
+	Tree
+)
+
+// Extension returns the extension of the golden files of the kind.
+func (k Kind) Extension() string {
+	if k == Tree {
+		return ".tree"
+	}
+	return ".tokens"
+}
+
+func (k Kind) String() string {
+	if k == Tree {
+		return "parser"
+	}
+	return "lexer"
+}
+
+// Result is the check of a golden file.
+type Result struct {
+	Kind      Kind
+	Source    string
+	Golden    string
+	Construct string
+	Passed    bool
+	// Diff is the difference from the golden when the check failed.
+	Diff string
+	// Err is set when the files can't be read or the golden isn't json.
+	Err error
+}
+
+// Stats are the results of a construct.
+type Stats struct {
+	Kind      Kind
+	Construct string
+	Passed    int
+	Total     int
+}
+
+// Run checks the goldens found in dirs, sorted by path.
+func Run(dirs ...string) ([]Result, error) {
+	var results []Result
+	for _, dir := range dirs {
+		goldens, err := Goldens(dir)
+		if err != nil {
+			return nil, err
+		}
+		for _, golden := range goldens {
+			results = append(results, Check(golden))
+		}
+	}
+	return results, nil
+}
+
+// Goldens returns the .tokens and .tree files of the .php files in dir and
+// its subdirectories.
+func Goldens(dir string) ([]string, error) {
+	var goldens []string
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() && (strings.HasSuffix(path, ".php.tokens") || strings.HasSuffix(path, ".php.tree")) {
+			goldens = append(goldens, path)
+		}
+		return nil
+	})
+	return goldens, err
+}
+
+// SourceOf returns the case of a golden file and the kind of the golden.
+func SourceOf(golden string) (string, Kind) {
+	kind := Tokens
+	if strings.HasSuffix(golden, Tree.Extension()) {
+		kind = Tree
+	}
+	return strings.TrimSuffix(golden, kind.Extension()), kind
+}
+
+// Check compares the tokens or the tree of a case with its golden file.
+func Check(golden string) Result {
+	source, kind := SourceOf(golden)
+	result := Result{Kind: kind, Source: source, Golden: golden, Construct: Construct(source)}
+
+	content, err := ioutil.ReadFile(source)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+	expected, err := ioutil.ReadFile(golden)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+
+	var actual []byte
+	if kind == Tree {
+		actual = treeJSON(content)
+	} else {
+		// the tokens are wrapped because the differ compares objects
+		var expectedTokens []lexer.TokenCompareForm
+		if err := json.Unmarshal(expected, &expectedTokens); err != nil {
+			result.Err = fmt.Errorf("%s: %v", golden, err)
+			return result
+		}
+		expected, _ = json.Marshal(map[string]interface{}{"_": expectedTokens})
+		actual = tokensJSON(content)
+	}
+
+	result.Passed, result.Diff, err = compare(actual, expected)
+	if err != nil {
+		result.Err = fmt.Errorf("%s: %v", golden, err)
+	}
+	return result
+}
+
+func tokensJSON(content []byte) []byte {
+	stream := lexer.TokensStream{}
+	stream.Source(content)
+	stream.CreateTokens()
+	actual, _ := json.Marshal(map[string]interface{}{"_": stream.Serialize()})
+	return actual
+}
+
+func treeJSON(content []byte) []byte {
+	p := parser.Parser{}
+	sourceFile := p.ParseSourceFile(content, "")
+	actual, _ := json.Marshal(ast.Serialize(sourceFile))
+	return actual
+}
+
+// compare returns whether actual and expected are the same json and the
+// difference when they aren't.
+func compare(actual, expected []byte) (bool, string, error) {
+	d, err := diff.New().Compare(actual, expected)
+	if err != nil {
+		return false, "", err
+	}
+	if !d.Modified() {
+		return true, "", nil
+	}
+	var aJson map[string]interface{}
+	json.Unmarshal(actual, &aJson)
+	config := formatter.AsciiFormatterConfig{
+		ShowArrayIndex: true,
+		Coloring:       false,
+	}
+	diffString, err := formatter.NewAsciiFormatter(aJson, config).Format(d)
+	return false, diffString, err
+}
+
+// Construct returns the construct a case covers, its file name up to the
+// number of the case, e.g. unaryExpression for unaryExpression6.php and
+// yieldExpression for yieldExpression1_from.php.
+func Construct(source string) string {
+	name := strings.TrimSuffix(filepath.Base(source), ".php")
+	if i := strings.IndexAny(name, "0123456789"); i > 0 {
+		return strings.TrimRight(name[:i], "_")
+	}
+	return name
+}
+
+// Summarize counts the passed results of each construct, sorted by kind
+// and construct.
+func Summarize(results []Result) []Stats {
+	index := map[Kind]map[string]int{}
+	var stats []Stats
+	for _, result := range results {
+		if index[result.Kind] == nil {
+			index[result.Kind] = map[string]int{}
+		}
+		i, ok := index[result.Kind][result.Construct]
+		if !ok {
+			i = len(stats)
+			index[result.Kind][result.Construct] = i
+			stats = append(stats, Stats{Kind: result.Kind, Construct: result.Construct})
+		}
+		stats[i].Total++
+		if result.Passed {
+			stats[i].Passed++
+		}
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Kind != stats[j].Kind {
+			return stats[i].Kind < stats[j].Kind
+		}
+		return stats[i].Construct < stats[j].Construct
+	})
+	return stats
+}
+
+// Regenerate writes the golden of a kind of source with mstpp, running
+// script (debug/debug.php) with the php binary.
+func Regenerate(php, script, source string, kind Kind) error {
+	command := "gencase-tokens"
+	if kind == Tree {
+		command = "gencase-parser"
+	}
+	out, err := exec.Command(php, script, command, source).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%s %s %s %s: %v: %s", php, script, command, source, err, out)
+	}
+	return nil
+}
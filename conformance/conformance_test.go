@@ -0,0 +1,127 @@
+package conformance
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/emilioastarita/gphp/lexer"
+)
+
+func TestConstruct(t *testing.T) {
+	cases := map[string]string{
+		"cases/unaryExpression6.php":           "unaryExpression",
+		"cases/yieldExpression14_from.php":     "yieldExpression",
+		"cases/ifStatement12_1.php":            "ifStatement",
+		"cases/core_predefined_constants2.php": "core_predefined_constants",
+		"cases/void_parameter.php":             "void_parameter",
+	}
+	for source, construct := range cases {
+		if got := Construct(source); got != construct {
+			t.Errorf("Construct(%q) = %q, expected %q", source, got, construct)
+		}
+	}
+}
+
+func TestRun(t *testing.T) {
+	dir, err := ioutil.TempDir("", "conformance")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	write := func(name string, content []byte) {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), content, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	tokens := func(source string) []byte {
+		stream := lexer.TokensStream{}
+		stream.Source([]byte(source))
+		stream.CreateTokens()
+		content, _ := json.Marshal(stream.Serialize())
+		return content
+	}
+
+	write("echo1.php", []byte("<?php echo $a;"))
+	write("echo1.php.tokens", tokens("<?php echo $a;"))
+	write("echo1.php.tree", treeJSON([]byte("<?php echo $a;")))
+	write("echo2.php", []byte("<?php echo $b;"))
+	write("echo2.php.tree", treeJSON([]byte("<?php echo $bb;")))
+	write("print1.php", []byte("<?php print $a;"))
+	write("print1.php.tree", []byte("<?php print $a;"))
+
+	results, err := Run(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 4 {
+		t.Fatalf("expected 4 results, got %d", len(results))
+	}
+	for _, result := range results {
+		name := filepath.Base(result.Golden)
+		switch name {
+		case "echo1.php.tokens", "echo1.php.tree":
+			if !result.Passed || result.Err != nil {
+				t.Errorf("%s should pass: %v %s", name, result.Err, result.Diff)
+			}
+		case "echo2.php.tree":
+			if result.Passed || result.Diff == "" {
+				t.Errorf("%s should fail with a diff", name)
+			}
+		case "print1.php.tree":
+			if result.Passed || result.Err == nil {
+				t.Errorf("%s isn't json and should fail with an error", name)
+			}
+		}
+	}
+
+	expected := []Stats{
+		{Tokens, "echo", 1, 1},
+		{Tree, "echo", 1, 2},
+		{Tree, "print", 0, 1},
+	}
+	stats := Summarize(results)
+	if len(stats) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, stats)
+	}
+	for i := range expected {
+		if stats[i] != expected[i] {
+			t.Errorf("expected %v, got %v", expected[i], stats[i])
+		}
+	}
+}
+
+func TestRegenerate(t *testing.T) {
+	sh, err := exec.LookPath("sh")
+	if err != nil {
+		t.Skip("no sh to stand in for php")
+	}
+	dir, err := ioutil.TempDir("", "conformance")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// the script gets the command and the source, as debug.php does
+	script := filepath.Join(dir, "debug.sh")
+	ioutil.WriteFile(script, []byte("[ \"$2\" = fail ] && { echo broken; exit 1; }\necho \"$1\" > \"$2.golden\"\n"), 0644)
+	source := filepath.Join(dir, "echo1.php")
+
+	for kind, command := range map[Kind]string{Tokens: "gencase-tokens", Tree: "gencase-parser"} {
+		if err := Regenerate(sh, script, source, kind); err != nil {
+			t.Fatalf("%v: %v", kind, err)
+		}
+		out, _ := ioutil.ReadFile(source + ".golden")
+		if strings.TrimSpace(string(out)) != command {
+			t.Errorf("%v: ran %q, expected %q", kind, out, command)
+		}
+	}
+	if err := Regenerate(sh, script, "fail", Tree); err == nil || !strings.Contains(err.Error(), "broken") {
+		t.Errorf("expected the output of the failed command in the error, got %v", err)
+	}
+}
@@ -4,41 +4,116 @@ import (
 	"encoding/json"
 	"fmt"
 	"github.com/emilioastarita/gphp/ast"
+	"github.com/emilioastarita/gphp/conformance"
 	"github.com/emilioastarita/gphp/lexer"
+	"github.com/emilioastarita/gphp/nikic"
 	"github.com/emilioastarita/gphp/parser"
 	diff "github.com/yudai/gojsondiff"
 	"github.com/yudai/gojsondiff/formatter"
+	"io"
 	"io/ioutil"
 	"log"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strings"
 )
 
 func printUsage() {
-	fmt.Println("Usage " + os.Args[0] + " [compare] scan|parse filename")
+	fmt.Println("Usage " + os.Args[0] + " [compare] scan|parse [--format=php-tokens|nikic|dot|sexp|tree] [--encoding=latin1|windows-1252] [--recovery] [--trace] [--php=/usr/bin/php] filename")
+	fmt.Println("      " + os.Args[0] + " conformance [--verbose] [--regenerate] [--php=php] [--script=debug.php] [dir...]")
 }
 
+// splitOptions separates --name=value flags from the positional arguments.
+func splitOptions(args []string) ([]string, map[string]string) {
+	positional := make([]string, 0, len(args))
+	options := map[string]string{}
+	for _, arg := range args {
+		if !strings.HasPrefix(arg, "--") {
+			positional = append(positional, arg)
+			continue
+		}
+		nameValue := strings.SplitN(arg[2:], "=", 2)
+		if len(nameValue) == 2 {
+			options[nameValue[0]] = nameValue[1]
+		} else {
+			options[nameValue[0]] = ""
+		}
+	}
+	return positional, options
+}
+
+// lexerOptions are the options used to scan and parse files.
+var lexerOptions lexer.Options
+
+// indentRecovery enables parser.Options.IndentRecovery.
+var indentRecovery bool
+
+// trace prints the trace events of the parser to stderr.
+var trace bool
+
+// phpBinary runs debug.php to compare with mstpp.
+var phpBinary = "/usr/bin/php"
+
 func main() {
-	largs := len(os.Args)
+	args, options := splitOptions(os.Args)
+	largs := len(args)
+	if php, ok := options["php"]; ok {
+		phpBinary = php
+	}
+	_, indentRecovery = options["recovery"]
+	_, trace = options["trace"]
+	if largs >= 2 && args[1] == "conformance" {
+		os.Exit(runConformance(args[2:], options))
+	}
 	if largs < 3 {
 		printUsage()
 		return
 	}
+	switch options["encoding"] {
+	case "", "utf-8":
+	case "latin1":
+		lexerOptions.Encoding = lexer.EncodingLatin1
+	case "windows-1252":
+		lexerOptions.Encoding = lexer.EncodingWindows1252
+	default:
+		printUsage()
+		return
+	}
 	isCompare := largs == 4
-	action := os.Args[1]
+	action := args[1]
 	subAction := ""
-	filename := os.Args[2]
+	filename := args[2]
 
 	if isCompare {
-		subAction = os.Args[2]
-		filename = os.Args[3]
+		subAction = args[2]
+		filename = args[3]
 	}
 
 	if action == "scan" {
-		fnWalk(filename, printTokensFromFile)
+		switch options["format"] {
+		case "":
+			fnWalk(filename, printTokensFromFile)
+		case "php-tokens":
+			fnWalk(filename, printPhpTokensFromFile)
+		default:
+			printUsage()
+		}
 	} else if action == "parse" {
-		fnWalk(filename, printAstFromFile)
+		switch options["format"] {
+		case "":
+			fnWalk(filename, printAstFromFile)
+		case "nikic":
+			fnWalk(filename, printNikicFromFile)
+		case "dot":
+			fnWalk(filename, func(file string) { printDumpFromFile(file, ast.FprintDot) })
+		case "sexp":
+			fnWalk(filename, func(file string) { printDumpFromFile(file, ast.FprintSexp) })
+		case "tree":
+			fnWalk(filename, func(file string) { printDumpFromFile(file, ast.FprintTree) })
+		default:
+			printUsage()
+		}
 	} else if action == "compare" {
 		if subAction == "scan" {
 			fnWalk(filename, printDiffWithPhpScan)
@@ -100,7 +175,7 @@ func printDiffWithPhpParser(filename string) {
 	p := parser.Parser{}
 	sourceCase, _ := ioutil.ReadFile(filename)
 	sourceFile := p.ParseSourceFile(sourceCase, "")
-	jsonSource, _ := json.Marshal(ast.Serialize(&sourceFile))
+	jsonSource, _ := json.Marshal(ast.Serialize(sourceFile))
 	resultCaseFromPhpParser := getMsParserOutput(filename, "parse")
 
 	differ := diff.New()
@@ -134,7 +209,7 @@ func printDiffWithPhpParser(filename string) {
 }
 
 func getMsParserOutput(filename string, action string) []byte {
-	cmd := "/usr/bin/php"
+	cmd := phpBinary
 	args := []string{"debug.php", action, filename}
 	out, err := exec.Command(cmd, args...).CombinedOutput()
 	if err != nil {
@@ -143,25 +218,130 @@ func getMsParserOutput(filename string, action string) []byte {
 	return out
 }
 
+// runConformance checks the goldens of dirs, the cases of the lexer and
+// the parser by default, and prints the pass rate of every construct. With
+// --regenerate the goldens are first written again by mstpp.
+func runConformance(dirs []string, options map[string]string) int {
+	if len(dirs) == 0 {
+		dirs = []string{"lexer/cases", "parser/cases"}
+	}
+	_, verbose := options["verbose"]
+
+	if _, regenerate := options["regenerate"]; regenerate {
+		php := options["php"]
+		if php == "" {
+			php = "php"
+		}
+		if _, err := exec.LookPath(php); err != nil {
+			fmt.Println("Can't regenerate the goldens without php:", err)
+			return 2
+		}
+		script := options["script"]
+		if script == "" {
+			script = "debug.php"
+		}
+		for _, dir := range dirs {
+			goldens, err := conformance.Goldens(dir)
+			if err != nil {
+				fmt.Println(err)
+				return 2
+			}
+			for _, golden := range goldens {
+				source, kind := conformance.SourceOf(golden)
+				if err := conformance.Regenerate(php, script, source, kind); err != nil {
+					fmt.Println(err)
+					return 2
+				}
+			}
+		}
+	}
+
+	results, err := conformance.Run(dirs...)
+	if err != nil {
+		fmt.Println(err)
+		return 2
+	}
+	passed := map[conformance.Kind]int{}
+	total := map[conformance.Kind]int{}
+	for _, result := range results {
+		total[result.Kind]++
+		if result.Passed {
+			passed[result.Kind]++
+			continue
+		}
+		fmt.Println("Fail: ", result.Golden)
+		if result.Err != nil {
+			fmt.Println(result.Err)
+		} else if verbose {
+			fmt.Println("START DIFF")
+			fmt.Println(result.Diff)
+			fmt.Println("END DIFF")
+		}
+	}
+
+	for _, stats := range conformance.Summarize(results) {
+		fmt.Printf("%-7s %-45s %4d/%-4d %6.1f%%\n", stats.Kind, stats.Construct, stats.Passed, stats.Total, percent(stats.Passed, stats.Total))
+	}
+	for _, kind := range []conformance.Kind{conformance.Tokens, conformance.Tree} {
+		if total[kind] > 0 {
+			fmt.Printf("%-7s %-45s %4d/%-4d %6.1f%%\n", kind, "total", passed[kind], total[kind], percent(passed[kind], total[kind]))
+		}
+	}
+	if passed[conformance.Tokens]+passed[conformance.Tree] != len(results) {
+		return 1
+	}
+	return 0
+}
+
+func percent(passed, total int) float64 {
+	return 100 * float64(passed) / float64(total)
+}
+
 func printAstFromFile(filename string) {
 	data, err := ioutil.ReadFile(filename)
 	if err != nil {
 		fmt.Println("Can't read file:", filename)
 		panic(err)
 	}
-	
+
 	printAst(data)
 }
 
-func printTokensFromFile(filename string) {
+func printNikicFromFile(filename string) {
 	data, err := ioutil.ReadFile(filename)
 	if err != nil {
 		fmt.Println("Can't read file:", filename)
 		panic(err)
 	}
 
+	p := parser.New(parser.Options{Lexer: lexerOptions, IndentRecovery: indentRecovery})
+	sourceFile := p.ParseSourceFile(data, "")
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "    ")
+	encoder.Encode(nikic.Export(sourceFile))
+}
 
-	stream := lexer.TokensStream{}
+func printDumpFromFile(filename string, fprint func(io.Writer, ast.Node, []byte) error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		fmt.Println("Can't read file:", filename)
+		panic(err)
+	}
+
+	p := parser.New(parser.Options{Lexer: lexerOptions, IndentRecovery: indentRecovery})
+	sourceFile := p.ParseSourceFile(data, "")
+	fprint(os.Stdout, sourceFile, sourceFile.FileContents)
+}
+
+func printTokensFromFile(filename string) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		fmt.Println("Can't read file:", filename)
+		panic(err)
+	}
+
+	stream := lexer.TokensStream{Options: lexerOptions}
 	stream.Source(data)
 	stream.CreateTokens()
 	jsonSource, _ := json.Marshal(stream.Serialize())
@@ -169,11 +349,32 @@ func printTokensFromFile(filename string) {
 	fmt.Println(string(pretty))
 }
 
+func printPhpTokensFromFile(filename string) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		fmt.Println("Can't read file:", filename)
+		panic(err)
+	}
+
+	stream := lexer.TokensStream{Options: lexerOptions}
+	stream.Source(data)
+	stream.CreateTokens()
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "    ")
+	encoder.Encode(stream.PhpTokens())
+}
+
 func printAst(content []byte) {
-	p := parser.Parser{}
+	p := parser.New(parser.Options{Lexer: lexerOptions, IndentRecovery: indentRecovery})
+	if trace {
+		p.Trace = func(event parser.TraceEvent) {
+			fmt.Fprintln(os.Stderr, event)
+		}
+	}
 	sourceFile := p.ParseSourceFile(content, "")
 
-	jsonSource, err := json.Marshal(ast.Serialize(&sourceFile))
+	jsonSource, err := json.Marshal(ast.Serialize(sourceFile))
 
 	if err != nil {
 		fmt.Println(err)
@@ -0,0 +1,514 @@
+<?php
+/**
+ * A small application kernel used to benchmark the lexer and the parser
+ * with code that looks like what real projects contain: namespaces,
+ * classes, closures, arrays, heredocs and interpolated strings.
+ */
+
+declare(strict_types=1);
+
+namespace App\Kernel;
+
+use App\Contracts\{Container as ContainerContract, Middleware};
+use App\Http\Request;
+use App\Http\Response;
+use App\Support\Collection;
+use InvalidArgumentException;
+use RuntimeException;
+use function array_map, array_filter, sprintf;
+use const PHP_EOL;
+
+interface Bootable
+{
+    public function boot(Container $container): void;
+}
+
+interface ServiceProvider extends Bootable
+{
+    const PRIORITY_LOW = 10;
+    const PRIORITY_NORMAL = 50;
+    const PRIORITY_HIGH = 100;
+
+    public function register(Container $container);
+
+    public function priority(): int;
+}
+
+trait HasAttributes
+{
+    /** @var array<string, mixed> */
+    protected $attributes = [];
+
+    public function __get($name)
+    {
+        return $this->attributes[$name] ?? null;
+    }
+
+    public function __set($name, $value)
+    {
+        $this->attributes[$name] = $value;
+    }
+
+    public function __isset($name): bool
+    {
+        return isset($this->attributes[$name]);
+    }
+
+    public function __unset($name)
+    {
+        unset($this->attributes[$name]);
+    }
+
+    public function toArray(): array
+    {
+        return array_map(function ($value) {
+            return $value instanceof self ? $value->toArray() : $value;
+        }, $this->attributes);
+    }
+}
+
+final class Container implements ContainerContract, \ArrayAccess
+{
+    private static $instance;
+
+    private $bindings = [];
+    private $instances = [];
+    private $aliases = [];
+    private $resolving = [];
+
+    public static function getInstance(): self
+    {
+        if (static::$instance === null) {
+            static::$instance = new static();
+        }
+
+        return static::$instance;
+    }
+
+    public function bind(string $abstract, $concrete = null, bool $shared = false): void
+    {
+        if ($concrete === null) {
+            $concrete = $abstract;
+        }
+
+        if (!$concrete instanceof \Closure) {
+            $concrete = function (Container $container, array $parameters = []) use ($abstract, $concrete) {
+                if ($abstract === $concrete) {
+                    return $container->build($concrete, $parameters);
+                }
+                return $container->make($concrete, $parameters);
+            };
+        }
+
+        $this->bindings[$abstract] = compact('concrete', 'shared');
+        unset($this->instances[$abstract], $this->aliases[$abstract]);
+    }
+
+    public function singleton(string $abstract, $concrete = null): void
+    {
+        $this->bind($abstract, $concrete, true);
+    }
+
+    public function alias(string $abstract, string $alias): void
+    {
+        if ($alias === $abstract) {
+            throw new InvalidArgumentException("[{$abstract}] is aliased to itself.");
+        }
+        $this->aliases[$alias] = $abstract;
+    }
+
+    public function make(string $abstract, array $parameters = [])
+    {
+        $abstract = $this->aliases[$abstract] ?? $abstract;
+
+        if (isset($this->instances[$abstract]) && empty($parameters)) {
+            return $this->instances[$abstract];
+        }
+
+        if (isset($this->resolving[$abstract])) {
+            throw new RuntimeException(sprintf('Circular dependency while resolving "%s".', $abstract));
+        }
+        $this->resolving[$abstract] = true;
+
+        try {
+            $concrete = $this->bindings[$abstract]['concrete'] ?? $abstract;
+            $object = $concrete instanceof \Closure
+                ? $concrete($this, $parameters)
+                : $this->build($concrete, $parameters);
+        } finally {
+            unset($this->resolving[$abstract]);
+        }
+
+        if (!empty($this->bindings[$abstract]['shared'])) {
+            $this->instances[$abstract] = $object;
+        }
+
+        return $object;
+    }
+
+    public function build(string $concrete, array $parameters = [])
+    {
+        try {
+            $reflector = new \ReflectionClass($concrete);
+        } catch (\ReflectionException $e) {
+            throw new RuntimeException("Target class [$concrete] does not exist.", 0, $e);
+        }
+
+        if (!$reflector->isInstantiable()) {
+            throw new RuntimeException("Target [$concrete] is not instantiable.");
+        }
+
+        $constructor = $reflector->getConstructor();
+        if (is_null($constructor)) {
+            return new $concrete;
+        }
+
+        $dependencies = [];
+        foreach ($constructor->getParameters() as $index => $parameter) {
+            $name = $parameter->getName();
+            if (array_key_exists($name, $parameters)) {
+                $dependencies[] = $parameters[$name];
+                continue;
+            }
+
+            $type = $parameter->getType();
+            if ($type !== null && !$type->isBuiltin()) {
+                $dependencies[] = $this->make($type->getName());
+            } elseif ($parameter->isDefaultValueAvailable()) {
+                $dependencies[] = $parameter->getDefaultValue();
+            } else {
+                throw new RuntimeException(
+                    "Unresolvable dependency \$$name (#$index) in class {$parameter->getDeclaringClass()->getName()}"
+                );
+            }
+        }
+
+        return $reflector->newInstanceArgs($dependencies);
+    }
+
+    public function offsetExists($key)
+    {
+        return isset($this->bindings[$key]) || isset($this->instances[$key]) || isset($this->aliases[$key]);
+    }
+
+    public function offsetGet($key)
+    {
+        return $this->make($key);
+    }
+
+    public function offsetSet($key, $value)
+    {
+        $this->bind($key, $value instanceof \Closure ? $value : function () use ($value) {
+            return $value;
+        });
+    }
+
+    public function offsetUnset($key)
+    {
+        unset($this->bindings[$key], $this->instances[$key], $this->aliases[$key]);
+    }
+}
+
+abstract class Route
+{
+    use HasAttributes;
+
+    protected const METHODS = ['GET', 'HEAD', 'POST', 'PUT', 'PATCH', 'DELETE', 'OPTIONS'];
+
+    protected $methods;
+    protected $uri;
+    protected $action;
+    protected $wheres = [];
+    protected $middleware = [];
+
+    public function __construct(array $methods, string $uri, $action)
+    {
+        $this->methods = array_map('strtoupper', $methods);
+        $this->uri = '/' . trim($uri, '/');
+        $this->action = $action;
+
+        if (in_array('GET', $this->methods, true) && !in_array('HEAD', $this->methods, true)) {
+            $this->methods[] = 'HEAD';
+        }
+    }
+
+    abstract public function run(Request $request): Response;
+
+    public function where($name, string $expression = null): self
+    {
+        foreach (is_array($name) ? $name : [$name => $expression] as $key => $pattern) {
+            $this->wheres[$key] = $pattern;
+        }
+        return $this;
+    }
+
+    public function middleware(string ...$middleware): self
+    {
+        $this->middleware = array_merge($this->middleware, $middleware);
+        return $this;
+    }
+
+    public function compile(): string
+    {
+        $pattern = preg_replace_callback('/\{(\w+)(\?)?\}/', function (array $matches): string {
+            list(, $name, $optional) = $matches + [null, null, ''];
+            $regex = $this->wheres[$name] ?? '[^/]+';
+            return $optional === '?' ? "(?:(?P<$name>$regex))?" : "(?P<$name>$regex)";
+        }, $this->uri);
+
+        return '#^' . $pattern . '$#sDu';
+    }
+
+    public function matches(Request $request, array &$parameters = null): bool
+    {
+        if (!in_array($request->method(), $this->methods, true)) {
+            return false;
+        }
+
+        if (preg_match($this->compile(), $request->path(), $matches) !== 1) {
+            return false;
+        }
+
+        $parameters = array_filter($matches, 'is_string', ARRAY_FILTER_USE_KEY);
+        return true;
+    }
+}
+
+class ClosureRoute extends Route
+{
+    public function run(Request $request): Response
+    {
+        $this->matches($request, $parameters);
+        $result = ($this->action)($request, ...array_values($parameters));
+
+        switch (true) {
+            case $result instanceof Response:
+                return $result;
+            case is_array($result):
+            case $result instanceof \JsonSerializable:
+                return Response::json($result);
+            case is_string($result):
+            case is_numeric($result):
+                return new Response((string) $result);
+            default:
+                return new Response('', 204);
+        }
+    }
+}
+
+class Router
+{
+    private $routes = [];
+    private $groupStack = [];
+
+    public function get(string $uri, $action): Route
+    {
+        return $this->addRoute(['GET'], $uri, $action);
+    }
+
+    public function post(string $uri, $action): Route
+    {
+        return $this->addRoute(['POST'], $uri, $action);
+    }
+
+    public function group(array $attributes, callable $routes): void
+    {
+        $this->groupStack[] = $attributes;
+        try {
+            $routes($this);
+        } finally {
+            array_pop($this->groupStack);
+        }
+    }
+
+    protected function addRoute(array $methods, string $uri, $action): Route
+    {
+        $prefix = '';
+        $middleware = [];
+        foreach ($this->groupStack as $group) {
+            $prefix .= '/' . trim($group['prefix'] ?? '', '/');
+            $middleware = array_merge($middleware, $group['middleware'] ?? []);
+        }
+
+        $route = new ClosureRoute($methods, $prefix . '/' . ltrim($uri, '/'), $action);
+        $route->middleware(...$middleware);
+
+        return $this->routes[] = $route;
+    }
+
+    public function dispatch(Request $request): Response
+    {
+        foreach ($this->routes as $i => $route) {
+            if ($route->matches($request)) {
+                $pipeline = array_reduce(
+                    array_reverse($route->middleware),
+                    static function (\Closure $next, Middleware $middleware) {
+                        return static function (Request $request) use ($middleware, $next) {
+                            return $middleware->handle($request, $next);
+                        };
+                    },
+                    function (Request $request) use ($route) {
+                        return $route->run($request);
+                    }
+                );
+                return $pipeline($request);
+            }
+        }
+
+        return new Response(<<<HTML
+<!doctype html>
+<html>
+    <head><title>Not found</title></head>
+    <body>
+        <h1>404</h1>
+        <p>No route matches {$request->method()} <code>{$request->path()}</code>.</p>
+    </body>
+</html>
+HTML
+, 404);
+    }
+}
+
+class Kernel
+{
+    const VERSION = '1.4.2';
+
+    /** @var ServiceProvider[] */
+    protected $providers = [];
+    protected $booted = false;
+    protected $startedAt;
+
+    protected $container;
+
+    public function __construct(Container $container)
+    {
+        $this->container = $container;
+        $this->startedAt = microtime(true);
+    }
+
+    public function register(ServiceProvider $provider): self
+    {
+        $provider->register($this->container);
+        $this->providers[] = $provider;
+
+        usort($this->providers, function (ServiceProvider $a, ServiceProvider $b): int {
+            return $b->priority() <=> $a->priority();
+        });
+
+        return $this;
+    }
+
+    public function boot(): void
+    {
+        if ($this->booted) {
+            return;
+        }
+
+        foreach ($this->providers as $provider) {
+            $provider->boot($this->container);
+        }
+        $this->booted = true;
+    }
+
+    public function handle(Request $request): Response
+    {
+        $this->boot();
+
+        try {
+            $response = $this->container->make(Router::class)->dispatch($request);
+        } catch (\Throwable $e) {
+            $response = $this->renderException($e);
+        }
+
+        $elapsed = (microtime(true) - $this->startedAt) * 1000;
+        $response->header('X-Runtime', sprintf('%.2fms', $elapsed));
+        $response->header('X-Powered-By', 'kernel/' . self::VERSION);
+
+        return $response;
+    }
+
+    protected function renderException(\Throwable $e): Response
+    {
+        $trace = '';
+        foreach ($e->getTrace() as $i => $frame) {
+            $trace .= "#$i {$frame['file']}({$frame['line']}): ";
+            $trace .= isset($frame['class']) ? $frame['class'] . $frame['type'] : '';
+            $trace .= $frame['function'] . '()' . PHP_EOL;
+        }
+
+        $status = $e instanceof InvalidArgumentException ? 400 : 500;
+        $message = htmlspecialchars($e->getMessage(), ENT_QUOTES | ENT_HTML5, 'UTF-8');
+
+        return new Response(<<<'TEMPLATE'
+<h1>Something went wrong</h1>
+TEMPLATE
+            . "<p>$message</p><pre>$trace</pre>", $status);
+    }
+
+    public function terminate(Request $request, Response $response): void
+    {
+        $log = [
+            'method' => $request->method(),
+            'path' => $request->path(),
+            'status' => $response->status(),
+            'memory' => memory_get_peak_usage(true) / 1024 / 1024,
+            'flags' => 0b1010 | 0x0F & ~07,
+            'ratio' => 1.5e-3 * 2,
+        ];
+
+        $line = '';
+        for ($i = 0, $keys = array_keys($log), $n = count($keys); $i < $n; ++$i) {
+            $line .= $keys[$i] . '=' . var_export($log[$keys[$i]], true) . ($i < $n - 1 ? ' ' : '');
+        }
+
+        do {
+            $written = @file_put_contents('php://stderr', $line . PHP_EOL, FILE_APPEND);
+        } while ($written === false && --$this->retries > 0);
+
+        while (ob_get_level() > 1) {
+            ob_end_flush();
+        }
+
+        if (function_exists('fastcgi_finish_request')) :
+            fastcgi_finish_request();
+        elseif (PHP_SAPI !== 'cli') :
+            flush();
+        endif;
+
+        goto done;
+        echo 'unreachable';
+        done:
+        return;
+    }
+}
+
+function collect(iterable $items = []): Collection
+{
+    static $factory;
+    global $config;
+
+    $factory = $factory ?: function ($items) use ($config) {
+        return new Collection($items, $config['collection'] ?? []);
+    };
+
+    return $factory($items);
+}
+
+function generate_ids(int $count, int $start = 1): \Generator
+{
+    for ($i = $start; $i < $start + $count; $i++) {
+        $ignored = yield $i => sprintf('%08d', $i);
+        if ($ignored) {
+            yield from generate_ids($count - $i, $i + 1);
+            return;
+        }
+    }
+}
+
+$kernel = new Kernel(Container::getInstance());
+$request = Request::capture();
+$response = $kernel->handle($request);
+$response->send();
+$kernel->terminate($request, $response);
+?>
+<!-- rendered by kernel <?= Kernel::VERSION ?> in <?php echo round((microtime(true) - $_SERVER['REQUEST_TIME_FLOAT']) * 1000, 2) ?>ms -->
@@ -0,0 +1,33 @@
+package lexer
+
+// DiagnosticKind is the kind of a Diagnostic. The lexical kinds are below,
+// the parser package defines its kinds from DiagnosticParserKinds on.
+type DiagnosticKind int
+
+const (
+	DiagnosticUnterminatedComment DiagnosticKind = iota
+	DiagnosticUnterminatedString
+	DiagnosticUnterminatedHeredoc
+	DiagnosticInvalidEscapeSequence
+	DiagnosticInvalidNumericLiteral
+	DiagnosticUnexpectedCharacter
+)
+
+// DiagnosticParserKinds is the first kind of the parser diagnostics, the
+// kinds below it are lexical.
+const DiagnosticParserKinds DiagnosticKind = 100
+
+// Diagnostic is an error in a source, a lexical one or, from the parser, a
+// syntax one. Start and Length are byte offsets into the scanned content.
+// The lexer never stops on errors: the offending text is still covered by
+// tokens and scanning goes on.
+type Diagnostic struct {
+	Kind    DiagnosticKind
+	Start   int
+	Length  int
+	Message string
+}
+
+func (l *LexerScanner) addDiagnostic(kind DiagnosticKind, start int, end int, message string) {
+	l.diagnostics = append(l.diagnostics, Diagnostic{kind, start, end - start, message})
+}
@@ -0,0 +1,130 @@
+package lexer
+
+import (
+	"bytes"
+	"sort"
+	"unicode/utf8"
+)
+
+type Encoding int
+
+const (
+	// EncodingUTF8 is the default, the source is lexed as it is.
+	EncodingUTF8 Encoding = iota
+	EncodingLatin1
+	EncodingWindows1252
+)
+
+type ByteOrderMark int
+
+const (
+	NoBOM ByteOrderMark = iota
+	UTF8BOM
+	UTF16BEBOM
+	UTF16LEBOM
+)
+
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// DetectBOM returns the byte order mark at the start of content and its
+// length. Only UTF-8 sources can be lexed, UTF-16 marks are reported so
+// callers can reject those files.
+func DetectBOM(content []byte) (ByteOrderMark, int) {
+	switch {
+	case bytes.HasPrefix(content, utf8BOM):
+		return UTF8BOM, len(utf8BOM)
+	case bytes.HasPrefix(content, []byte{0xFE, 0xFF}):
+		return UTF16BEBOM, 2
+	case bytes.HasPrefix(content, []byte{0xFF, 0xFE}):
+		return UTF16LEBOM, 2
+	}
+	return NoBOM, 0
+}
+
+// prependedTextLength returns the length of the UTF-8 byte order mark and
+// the #! line that can precede the first inline html or start tag of a
+// file, new line included.
+func prependedTextLength(content []byte) int {
+	pos := 0
+	if bytes.HasPrefix(content, utf8BOM) {
+		pos = len(utf8BOM)
+	}
+	if bytes.HasPrefix(content[pos:], []byte("#!")) {
+		end := bytes.IndexByte(content[pos:], '\n')
+		if end == -1 {
+			return len(content)
+		}
+		pos += end + 1
+	}
+	return pos
+}
+
+// windows1252 holds the code points of the bytes 0x80-0x9F, the bytes
+// left undefined by Windows-1252 are mapped to the C1 controls like
+// Latin-1 does.
+var windows1252 = [32]rune{
+	0x20AC, 0x0081, 0x201A, 0x0192, 0x201E, 0x2026, 0x2020, 0x2021,
+	0x02C6, 0x2030, 0x0160, 0x2039, 0x0152, 0x008D, 0x017D, 0x008F,
+	0x0090, 0x2018, 0x2019, 0x201C, 0x201D, 0x2022, 0x2013, 0x2014,
+	0x02DC, 0x2122, 0x0161, 0x203A, 0x0153, 0x009D, 0x017E, 0x0178,
+}
+
+// OffsetMap maps offsets of a transcoded source back to the source it was
+// transcoded from. Only non ASCII chars change their length so an entry
+// is kept for each of them.
+type OffsetMap struct {
+	entries []offsetMapEntry
+}
+
+type offsetMapEntry struct {
+	offset         int
+	originalOffset int
+	length         int
+}
+
+// OriginalOffset returns the offset in the original source of the byte at
+// offset in the transcoded one. Offsets inside a transcoded char map to
+// the start of the original char.
+func (m *OffsetMap) OriginalOffset(offset int) int {
+	if m == nil {
+		return offset
+	}
+	i := sort.Search(len(m.entries), func(i int) bool {
+		return m.entries[i].offset > offset
+	})
+	if i == 0 {
+		return offset
+	}
+	e := m.entries[i-1]
+	if offset < e.offset+e.length {
+		return e.originalOffset
+	}
+	return e.originalOffset + 1 + offset - (e.offset + e.length)
+}
+
+// Transcode converts a Latin-1 or Windows-1252 source to UTF-8. Sources
+// starting with a UTF-8 byte order mark are already UTF-8 and are
+// returned as they are, as well as sources in EncodingUTF8. The returned
+// map is nil when the source is not transcoded.
+func Transcode(content []byte, encoding Encoding) ([]byte, *OffsetMap) {
+	if encoding == EncodingUTF8 || bytes.HasPrefix(content, utf8BOM) {
+		return content, nil
+	}
+	m := &OffsetMap{}
+	result := make([]byte, 0, len(content))
+	var buf [utf8.UTFMax]byte
+	for i, char := range content {
+		if char < 0x80 {
+			result = append(result, char)
+			continue
+		}
+		r := rune(char)
+		if encoding == EncodingWindows1252 && char <= 0x9F {
+			r = windows1252[char-0x80]
+		}
+		n := utf8.EncodeRune(buf[:], r)
+		m.entries = append(m.entries, offsetMapEntry{len(result), i, n})
+		result = append(result, buf[:n]...)
+	}
+	return result, m
+}
@@ -0,0 +1,71 @@
+//go:build go1.18
+// +build go1.18
+
+package lexer
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// fuzzTimeLimit is the time given to scan an input, far more than what a
+// linear scan of the inputs of the fuzzer takes.
+const fuzzTimeLimit = 2 * time.Second
+
+func FuzzLexer(f *testing.F) {
+	sourceFiles, _ := filepath.Glob("cases/*.php")
+	for _, sourceFile := range sourceFiles {
+		data, _ := ioutil.ReadFile(sourceFile)
+		f.Add(data)
+	}
+	f.Fuzz(func(t *testing.T, source []byte) {
+		for _, shortOpenTag := range []bool{false, true} {
+			stream := TokensStream{Options: Options{ShortOpenTag: shortOpenTag}}
+			stream.Source(source)
+			done := make(chan struct{})
+			go func() {
+				stream.CreateTokens()
+				close(done)
+			}()
+			select {
+			case <-done:
+			case <-time.After(fuzzTimeLimit):
+				t.Fatalf("scan takes more than %v", fuzzTimeLimit)
+			}
+			checkTokens(t, source, stream.Tokens)
+
+			for _, diagnostic := range stream.Diagnostics {
+				if diagnostic.Start < 0 || diagnostic.Length < 0 || diagnostic.Start+diagnostic.Length > len(source) {
+					t.Fatalf("diagnostic out of bounds %+v", diagnostic)
+				}
+			}
+		}
+
+		var text bytes.Buffer
+		for _, token := range TokenGetAll(source) {
+			text.WriteString(token.Text)
+		}
+		if !bytes.Equal(text.Bytes(), source) {
+			t.Fatalf("TokenGetAll texts don't reproduce the source:\n%q\n%q", text.Bytes(), source)
+		}
+	})
+}
+
+// checkTokens checks that tokens are in bounds, contiguous and that their
+// texts reproduce source.
+func checkTokens(t *testing.T, source []byte, tokens []*Token) {
+	pos := 0
+	for i, token := range tokens {
+		if token.FullStart != pos || token.Start < token.FullStart || token.Start > token.FullStart+token.Length ||
+			token.FullStart+token.Length > len(source) {
+			t.Fatalf("token %d %+v out of place, expected full start %d in %d bytes", i, token, pos, len(source))
+		}
+		pos = token.FullStart + token.Length
+	}
+	if len(tokens) == 0 || tokens[len(tokens)-1].Kind != EndOfFileToken || pos != len(source) {
+		t.Fatalf("tokens don't end with the end of file at %d", len(source))
+	}
+}
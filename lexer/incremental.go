@@ -0,0 +1,176 @@
+package lexer
+
+import (
+	"sort"
+)
+
+// checkpoint is a position between tokens where the lexer can be restarted
+// knowing only its state: it is never inside a string, a heredoc or a
+// comment. index is the index of the token that starts at pos.
+type checkpoint struct {
+	index int
+	pos   int
+	state LexerState
+}
+
+// maxLookahead is the longest distance the lexer looks past the end of a
+// token, besides the runs of whitespace and names skipped while looking
+// for casts, yield from and heredoc identifiers.
+const maxLookahead = 8
+
+// ApplyEdit replaces oldLength bytes at start with newText and relexes only
+// the tokens affected by the change. The scan restarts from a checkpoint
+// before the edit and stops as soon as it reaches a checkpoint of the
+// previous scan past the edit, from where the old tokens are kept and
+// shifted. The result is the same as scanning the edited source again.
+// Offsets are those of Content, Offsets is cleared when the source was
+// transcoded.
+//
+// The tokens kept after the edit are the same *Token values, shifted in
+// place, so ApplyEdit invalidates every token previously returned by the
+// stream: a tree or a slice holding them sees the new offsets, which are
+// wrong for the old source. Copy the tokens before the edit to keep them.
+func (s *TokensStream) ApplyEdit(start int, oldLength int, newText []byte) {
+	oldContent := s.lexer.content
+	if start < 0 || oldLength < 0 || start+oldLength > len(oldContent) {
+		panic("lexer: edit out of range")
+	}
+	content := make([]byte, 0, len(oldContent)-oldLength+len(newText))
+	content = append(content, oldContent[:start]...)
+	content = append(content, newText...)
+	content = append(content, oldContent[start+oldLength:]...)
+	s.Offsets = nil
+	delta := len(newText) - oldLength
+	oldEditEnd := start + oldLength
+
+	restart := s.checkpoints[restartCheckpoint(s.checkpoints, safeRestartPos(oldContent, start))]
+	oldCheckpoints := s.checkpoints
+	oldTokens := s.Tokens
+	oldDiagnostics := s.Diagnostics
+
+	s.lexer = LexerScanner{
+		s.lexer.options,
+		restart.state,
+		HereDocStateNone,
+		"",
+		restart.pos,
+		len(content),
+		0,
+		0,
+		content,
+		DoubleQuoteToken,
+		nil,
+		nil,
+	}
+
+	// the first old checkpoint that could match a new one
+	next := sort.Search(len(oldCheckpoints), func(i int) bool {
+		return oldCheckpoints[i].pos >= oldEditEnd
+	})
+	var converged checkpoint
+	stop := func(c checkpoint) bool {
+		oldPos := c.pos - delta
+		if oldPos < oldEditEnd {
+			return false
+		}
+		for next < len(oldCheckpoints) && oldCheckpoints[next].pos < oldPos {
+			next++
+		}
+		if next < len(oldCheckpoints) && oldCheckpoints[next].pos == oldPos && oldCheckpoints[next].state == c.state {
+			converged = oldCheckpoints[next]
+			return true
+		}
+		return false
+	}
+
+	tokens, checkpoints, isConverged := s.scanTokens(nil, nil, stop)
+
+	oldEnd := len(oldTokens)
+	oldEndPos := len(oldContent)
+	if isConverged {
+		oldEnd = converged.index
+		oldEndPos = converged.pos
+	}
+	tokenDelta := len(tokens) - (oldEnd - restart.index)
+
+	// splice tokens
+	for _, token := range oldTokens[oldEnd:] {
+		token.FullStart += delta
+		token.Start += delta
+	}
+	s.Tokens = append(append(append(make([]*Token, 0, len(oldTokens)+tokenDelta), oldTokens[:restart.index]...), tokens...), oldTokens[oldEnd:]...)
+
+	// splice checkpoints, the restart checkpoint is kept
+	restartIndex := sort.Search(len(oldCheckpoints), func(i int) bool {
+		return oldCheckpoints[i].pos > restart.pos
+	})
+	s.checkpoints = make([]checkpoint, 0, len(oldCheckpoints)+len(checkpoints))
+	s.checkpoints = append(s.checkpoints, oldCheckpoints[:restartIndex]...)
+	for _, c := range checkpoints {
+		c.index += restart.index
+		s.checkpoints = append(s.checkpoints, c)
+	}
+	if isConverged {
+		for _, c := range oldCheckpoints[next:] {
+			c.index += tokenDelta
+			c.pos += delta
+			s.checkpoints = append(s.checkpoints, c)
+		}
+	}
+
+	// splice diagnostics
+	var diagnostics []Diagnostic
+	inserted := false
+	for _, diagnostic := range oldDiagnostics {
+		if diagnostic.Start >= restart.pos && diagnostic.Start < oldEndPos {
+			continue
+		}
+		if diagnostic.Start >= oldEndPos {
+			if !inserted {
+				diagnostics = append(diagnostics, s.lexer.diagnostics...)
+				inserted = true
+			}
+			diagnostic.Start += delta
+		}
+		diagnostics = append(diagnostics, diagnostic)
+	}
+	if !inserted {
+		diagnostics = append(diagnostics, s.lexer.diagnostics...)
+	}
+	s.lexer.diagnostics = diagnostics
+	s.Diagnostics = diagnostics
+
+	s.lexer.pos = len(content)
+	s.Pos = 0
+	s.EofPos = len(s.Tokens) - 1
+}
+
+// safeRestartPos returns a position far enough before start that no token
+// scanned from there looked at the edited text when it was scanned.
+func safeRestartPos(content []byte, start int) int {
+	pos := start
+	for pos > 0 && isLookaheadSkippedChar(content[pos-1]) {
+		pos--
+	}
+	pos -= maxLookahead
+	if pos < 0 {
+		return 0
+	}
+	return pos
+}
+
+func isLookaheadSkippedChar(char byte) bool {
+	return char == ' ' || char == '\t' || char == '\n' || char == '\r' || char == ';' || char == '_' ||
+		char >= 'a' && char <= 'z' || char >= 'A' && char <= 'Z' || char >= '0' && char <= '9' || char >= 0x80
+}
+
+// restartCheckpoint returns the index of the last checkpoint at or before pos.
+func restartCheckpoint(checkpoints []checkpoint, pos int) int {
+	i := sort.Search(len(checkpoints), func(i int) bool {
+		return checkpoints[i].pos > pos
+	})
+	if i == 0 {
+		return 0
+	}
+	return i - 1
+}
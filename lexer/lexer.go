@@ -1,7 +1,8 @@
 package lexer
 
 import (
-	"strings"
+	"context"
+	"errors"
 	"unicode"
 	"unicode/utf8"
 )
@@ -20,7 +21,18 @@ const (
 	HereDocNowDoc
 )
 
+// Options changes how the source is tokenized, like the php.ini
+// settings of the same name.
+type Options struct {
+	// ShortOpenTag makes <? open a script section (short_open_tag=On).
+	ShortOpenTag bool
+	// Encoding of the source, Latin-1 and Windows-1252 sources are
+	// transcoded to UTF-8 before lexing.
+	Encoding Encoding
+}
+
 type LexerScanner struct {
+	options           Options
 	state             LexerState
 	hereDocStatus     HereDocStatus
 	hereDocIdentifier string
@@ -30,18 +42,29 @@ type LexerScanner struct {
 	start             int
 	content           []byte
 	stringDelimiter   TokenKind
+	diagnostics       []Diagnostic
+	// the tokens are allocated in chunks, see newToken
+	tokenChunk []Token
 }
 
 type TokensStream struct {
-	Tokens []*Token
-	Pos    int
-	EofPos int
-	tokenMem []*Token
-	lexer    LexerScanner
+	Options     Options
+	Tokens      []*Token
+	Diagnostics []Diagnostic
+	Pos         int
+	EofPos      int
+	tokenMem    []*Token
+	lexer       LexerScanner
+	checkpoints []checkpoint
+	// Offsets maps the offsets of the tokens back to the source passed to
+	// Source when it was transcoded, it is nil otherwise.
+	Offsets *OffsetMap
 }
 
 func (s *TokensStream) Source(content []byte) {
+	content, s.Offsets = Transcode(content, s.Options.Encoding)
 	s.lexer = LexerScanner{
+		s.Options,
 		LexStateHtmlSection,
 		HereDocStateNone,
 		"",
@@ -51,24 +74,129 @@ func (s *TokensStream) Source(content []byte) {
 		0,
 		content,
 		DoubleQuoteToken,
+		nil,
+		nil,
 	}
 	s.lexer.eofPos = len(s.lexer.content)
+	s.Tokens = nil
+	s.Diagnostics = nil
+	s.checkpoints = nil
+}
+
+// SourceFragment is like Source but the content is a piece of code
+// without the <?php tag, it is scanned as if it followed one.
+func (s *TokensStream) SourceFragment(content []byte) {
+	s.Source(content)
+	s.lexer.state = LexStateScriptSectionParsed
 }
 
 func (s *TokensStream) CreateTokens() {
-	lexer := s.lexer
+	s.allocate()
+	s.Tokens, s.checkpoints, _ = s.scanTokens(s.Tokens, s.checkpoints, nil)
+	s.Diagnostics = s.lexer.diagnostics
+	s.Pos = 0
+	s.EofPos = len(s.Tokens) - 1
+}
+
+// bytesPerToken is about the average length of the tokens of PHP code,
+// comments and whitespace included.
+const bytesPerToken = 5
+
+// allocate sizes the tokens and the checkpoints of a scan after the length
+// of the content, so that they are rarely grown.
+func (s *TokensStream) allocate() {
+	size := (s.lexer.eofPos-s.lexer.pos)/bytesPerToken + 2
+	if s.Tokens == nil {
+		s.Tokens = make([]*Token, 0, size)
+	}
+	s.checkpoints = make([]checkpoint, 1, size)
+	s.checkpoints[0] = checkpoint{0, s.lexer.pos, s.lexer.state}
+}
+
+// ErrMaxTokens is returned by CreateTokensContext when the source has more
+// tokens than allowed.
+var ErrMaxTokens = errors.New("lexer: maximum number of tokens exceeded")
+
+// checkContextEvery is the number of tokens scanned between two checks of
+// the context by CreateTokensContext.
+const checkContextEvery = 256
+
+// CreateTokensContext is like CreateTokens but stops when ctx is done, or
+// when the source has more than maxTokens tokens, the end of file included,
+// and maxTokens is not 0. The tokens of a stopped scan end with an
+// EndOfFileToken where the lexer stopped, exactly maxTokens tokens for
+// ErrMaxTokens, and the error is ctx.Err() or ErrMaxTokens.
+func (s *TokensStream) CreateTokensContext(ctx context.Context, maxTokens int) error {
+	var err error
+	// a counter and not the number of tokens, which can skip the multiples
+	// of checkContextEvery
+	checks := 0
+	stop := func(c checkpoint) bool {
+		checks++
+		// the end of file isn't scanned yet, it would be one token more
+		if maxTokens > 0 && c.index >= maxTokens {
+			err = ErrMaxTokens
+		} else if checks%checkContextEvery == 0 {
+			err = ctx.Err()
+		}
+		return err != nil
+	}
+	start := s.lexer.pos
+	s.allocate()
+	var stopped bool
+	s.Tokens, s.checkpoints, stopped = s.scanTokens(s.Tokens, s.checkpoints, stop)
+	if stopped {
+		end := s.lexer.pos
+		if err == ErrMaxTokens {
+			// a string can add several tokens past the limit
+			s.Tokens = s.Tokens[:maxTokens-1]
+			for len(s.checkpoints) > 1 && s.checkpoints[len(s.checkpoints)-1].index >= maxTokens {
+				s.checkpoints = s.checkpoints[:len(s.checkpoints)-1]
+			}
+			end = start
+			if len(s.Tokens) > 0 {
+				last := s.Tokens[len(s.Tokens)-1]
+				end = last.FullStart + last.Length
+			}
+		}
+		s.Tokens = append(s.Tokens, &Token{EndOfFileToken, end, end, 0, TokenCatNormal, nil})
+	}
+	s.Diagnostics = s.lexer.diagnostics
+	s.Pos = 0
+	s.EofPos = len(s.Tokens) - 1
+	return err
+}
+
+// scanTokens appends tokens until the end of file and records a checkpoint
+// after every token or string that leaves the lexer in a state from where
+// it can be restarted. If stop returns true for a checkpoint the scan ends
+// there and the checkpoint is not recorded.
+func (s *TokensStream) scanTokens(tokens []*Token, checkpoints []checkpoint, stop func(checkpoint) bool) ([]*Token, []checkpoint, bool) {
+	lexer := &s.lexer
 	token := &Token{}
 	for token.Kind != EndOfFileToken {
 		token, s.tokenMem = lexer.scan(nil)
 		if token.Kind == -1 {
-			s.Tokens = append(s.Tokens, s.tokenMem...)
+			tokens = append(tokens, s.tokenMem...)
 		} else {
-			s.Tokens = append(s.Tokens, token)
+			tokens = append(tokens, token)
 			lexer.pos = token.FullStart + token.Length
 		}
+		if token.Kind == EndOfFileToken || lexer.hereDocStatus != HereDocStateNone {
+			continue
+		}
+		c := checkpoint{len(tokens), lexer.pos, lexer.state}
+		if stop != nil && stop(c) {
+			return tokens, checkpoints, true
+		}
+		checkpoints = append(checkpoints, c)
 	}
-	s.Pos = 0
-	s.EofPos = len(s.Tokens) - 1
+	return tokens, checkpoints, false
+}
+
+// Content returns the lexed source, the tokens offsets are offsets of it.
+func (s *TokensStream) Content() []byte {
+	return s.lexer.content
 }
 
 func (s *TokensStream) ScanNext() *Token {
@@ -90,19 +218,38 @@ func (s *TokensStream) Serialize() []TokenCompareForm {
 }
 
 func (l *LexerScanner) addToMem(kind TokenKind, pos int, tokenMem []*Token) []*Token {
-	tokenMem = append(tokenMem, &Token{kind, l.fullStart, l.start, pos - l.fullStart, TokenCatNormal})
+	tokenMem = append(tokenMem, l.newToken(kind, l.fullStart, l.start, pos-l.fullStart))
 	l.fullStart = pos
 	l.start = pos
 	return tokenMem
 }
 
 func (l *LexerScanner) addToMemInPlace(kind TokenKind, pos int, length int, tokenMem []*Token) []*Token {
-	tokenMem = append(tokenMem, &Token{kind, pos, pos, length, TokenCatNormal})
+	tokenMem = append(tokenMem, &Token{kind, pos, pos, length, TokenCatNormal, nil})
 	return tokenMem
 }
 
 func (l *LexerScanner) createToken(kind TokenKind) *Token {
-	return &Token{kind, l.fullStart, l.start, l.pos - l.fullStart, TokenCatNormal}
+	return l.newToken(kind, l.fullStart, l.start, l.pos-l.fullStart)
+}
+
+// tokenChunkSize is the number of tokens allocated at once by newToken.
+const tokenChunkSize = 128
+
+// newToken returns a new token taken from a chunk of tokens, allocating
+// them one by one is one of the costs of the scan.
+func (l *LexerScanner) newToken(kind TokenKind, fullStart int, start int, length int) *Token {
+	if len(l.tokenChunk) == 0 {
+		size := (l.eofPos-l.pos)/bytesPerToken + 2
+		if size > tokenChunkSize {
+			size = tokenChunkSize
+		}
+		l.tokenChunk = make([]Token, size)
+	}
+	token := &l.tokenChunk[0]
+	l.tokenChunk = l.tokenChunk[1:]
+	*token = Token{kind, fullStart, start, length, TokenCatNormal, nil}
+	return token
 }
 
 func (l *LexerScanner) scan(tokenMem []*Token) (*Token, []*Token) {
@@ -116,7 +263,7 @@ func (l *LexerScanner) scan(tokenMem []*Token) (*Token, []*Token) {
 			if l.state != LexStateHtmlSection {
 				current = l.createToken(EndOfFileToken)
 			} else {
-				current = &Token{InlineHtml, l.fullStart, l.fullStart, l.pos - l.fullStart, TokenCatNormal}
+				current = &Token{InlineHtml, l.fullStart, l.fullStart, l.pos - l.fullStart, TokenCatNormal, nil}
 			}
 			l.state = LexStateScriptSection
 			if current.Kind == InlineHtml && l.pos-l.fullStart == 0 {
@@ -126,8 +273,15 @@ func (l *LexerScanner) scan(tokenMem []*Token) (*Token, []*Token) {
 		}
 
 		if l.state == LexStateHtmlSection {
+			// the byte order mark and the #! line are not inline html
+			if l.pos == 0 {
+				if length := prependedTextLength(l.content[:l.eofPos]); length > 0 {
+					l.pos = length
+					return l.createToken(ScriptSectionPrependedText), tokenMem
+				}
+			}
 			// Keep scanning until we hit a script section Start tag
-			if !isScriptStartTag(l.content, l.pos, l.eofPos) {
+			if !isScriptStartTag(l.content, l.pos, l.eofPos, l.options.ShortOpenTag) {
 				l.pos++
 				continue
 			}
@@ -136,7 +290,7 @@ func (l *LexerScanner) scan(tokenMem []*Token) (*Token, []*Token) {
 			if l.pos-l.fullStart == 0 {
 				continue
 			}
-			return &Token{InlineHtml, l.fullStart, l.fullStart, l.pos - l.fullStart, TokenCatNormal}, tokenMem
+			return &Token{InlineHtml, l.fullStart, l.fullStart, l.pos - l.fullStart, TokenCatNormal, nil}, tokenMem
 		}
 
 		charCode := l.content[l.pos]
@@ -190,8 +344,7 @@ func (l *LexerScanner) scan(tokenMem []*Token) (*Token, []*Token) {
 			}
 
 			if l.pos+1 < l.eofPos && charCode == '.' && isDigitChar(rune(l.content[l.pos+1])) {
-				kind := scanNumericLiteral(l.content, &l.pos, l.eofPos)
-				return l.createToken(kind), tokenMem
+				return l.scanNumericLiteralToken(), tokenMem
 			}
 
 			// we must check for cast tokens
@@ -208,7 +361,11 @@ func (l *LexerScanner) scan(tokenMem []*Token) (*Token, []*Token) {
 				scanSingleLineComment(l.content, &l.pos, l.eofPos, l.state)
 				continue
 			} else if isDelimitedCommentStart(l.content, l.pos, l.eofPos) {
-				scanDelimitedComment(l.content, &l.pos, l.eofPos)
+				commentStart := l.pos
+				l.pos += 2
+				if !scanDelimitedComment(l.content, &l.pos, l.eofPos) {
+					l.addDiagnostic(DiagnosticUnterminatedComment, commentStart, l.pos, "Unterminated comment starts here")
+				}
 				continue
 			} else if l.pos+1 < l.eofPos && l.content[l.pos+1] == '=' {
 				l.pos += 2
@@ -257,6 +414,7 @@ func parseDocNow(l *LexerScanner, tokenMem []*Token) []*Token {
 			continue
 		}
 	}
+	l.addDiagnostic(DiagnosticUnterminatedHeredoc, l.start, l.pos, "Unterminated nowdoc, '"+l.hereDocIdentifier+"' expected")
 	if hasEncapsed {
 		tokenMem = append(tokenMem, l.createToken(EncapsedAndWhitespace))
 		l.start, l.fullStart = l.pos, l.pos
@@ -273,15 +431,15 @@ func parseHeredoc(l *LexerScanner, tokenMem []*Token) []*Token {
 	fileContent := l.content
 	for {
 		if *pos >= eofPos {
-			// UNTERMINATED, report error
-			tokenMem = append(tokenMem, &Token{EncapsedAndWhitespace, l.fullStart, l.start, *pos - l.fullStart, TokenCatNormal})
+			l.addDiagnostic(DiagnosticUnterminatedHeredoc, startPosition, *pos, "Unterminated heredoc, '"+l.hereDocIdentifier+"' expected")
+			tokenMem = append(tokenMem, &Token{EncapsedAndWhitespace, l.fullStart, l.start, *pos - l.fullStart, TokenCatNormal, nil})
 			return tokenMem
 		}
 
 		char := l.content[*pos]
 
 		if *pos+1 < l.eofPos && isNewLineChar(rune(l.content[*pos])) && isNowdocEnd(l.hereDocIdentifier, l.content, *pos+1, l.eofPos) {
-			tokenMem = append(tokenMem, &Token{EncapsedAndWhitespace, l.fullStart, l.start, *pos - l.fullStart + 1, TokenCatNormal})
+			tokenMem = append(tokenMem, &Token{EncapsedAndWhitespace, l.fullStart, l.start, *pos - l.fullStart + 1, TokenCatNormal, nil})
 			*pos++
 			l.start, l.fullStart = *pos, *pos
 			*pos += len(l.hereDocIdentifier)
@@ -302,7 +460,7 @@ func parseHeredoc(l *LexerScanner, tokenMem []*Token) []*Token {
 				if *pos < eofPos && fileContent[*pos] == '[' {
 					*pos++
 					tokenMem = l.addToMem(OpenBracketToken, *pos, tokenMem)
-					if isDigitChar(rune(fileContent[*pos])) {
+					if *pos < eofPos && isDigitChar(rune(fileContent[*pos])) {
 						*pos++
 						scanName(fileContent, pos, eofPos)
 						tokenMem = l.addToMem(IntegerLiteralToken, *pos, tokenMem)
@@ -312,7 +470,7 @@ func parseHeredoc(l *LexerScanner, tokenMem []*Token) []*Token {
 						scanName(fileContent, pos, eofPos)
 						tokenMem = l.addToMem(Name, *pos, tokenMem)
 					}
-					if fileContent[*pos] == ']' {
+					if *pos < eofPos && fileContent[*pos] == ']' {
 						*pos++
 						tokenMem = l.addToMem(CloseBracketToken, *pos, tokenMem)
 					}
@@ -352,7 +510,7 @@ func parseHeredoc(l *LexerScanner, tokenMem []*Token) []*Token {
 		// Escape character
 		if char == '\\' {
 			*pos++
-			scanDqEscapeSequence(fileContent, pos, eofPos)
+			l.scanDqEscapeSequence(pos)
 			continue
 		}
 
@@ -397,11 +555,11 @@ func tryScanHeredocStart(l *LexerScanner) (TokenKind, bool) {
 
 	pos := l.pos + 3 // consume <<<
 
-	for unicode.IsSpace(rune(l.content[pos])) && !isNewLineChar(rune(l.content[pos])) {
+	for pos < l.eofPos && unicode.IsSpace(rune(l.content[pos])) && !isNewLineChar(rune(l.content[pos])) {
 		pos++
 	}
 
-	isNowDoc := l.content[pos] == '\''
+	isNowDoc := pos < l.eofPos && l.content[pos] == '\''
 
 	if isNowDoc {
 		pos++
@@ -413,11 +571,11 @@ func tryScanHeredocStart(l *LexerScanner) (TokenKind, bool) {
 	startIdentifier := pos
 	pos++
 
-	for ; pos < l.eofPos; {
+	for pos < l.eofPos {
 
 		charCode, size := utf8.DecodeRune(l.content[pos:])
 
-		if isValidNameUnicodeChar(charCode) {
+		if isValidNameUnicodeChar(charCode) || isDigitChar(charCode) || charCode == '_' {
 			pos += size
 			continue
 		} else if l.content[pos] == '\'' && isNowDoc == false {
@@ -429,12 +587,14 @@ func tryScanHeredocStart(l *LexerScanner) (TokenKind, bool) {
 				l.hereDocStatus = HereDocNowDoc
 				return HeredocStart, true
 			}
-		} else if isNewLineChar(rune(l.content[pos])) {
+			return foundTokenKind, false
+		} else if isNewLineChar(rune(l.content[pos])) && !isNowDoc {
 			l.hereDocIdentifier = string(l.content[startIdentifier:pos])
 			l.pos = pos + 1
 			l.hereDocStatus = HereDocNormal
 			return HeredocStart, true
 		}
+		return foundTokenKind, false
 	}
 	return foundTokenKind, false
 }
@@ -466,8 +626,7 @@ func tryScanCastToken(l *LexerScanner) (TokenKind, bool) {
 				continue
 			}
 
-			word := strings.ToLower(string(l.content[i : i+len(castString)]))
-			if word == castString {
+			if equalFoldASCII(l.content[i:i+len(castString)], castString) {
 				foundTokenKind = CAST_KEYWORDS_MAP[castString]
 				i = i + len(castString) - 1
 				break
@@ -482,48 +641,34 @@ func tryScanCastToken(l *LexerScanner) (TokenKind, bool) {
 	return foundTokenKind, false
 }
 
+// tryScanYieldFrom scans the rest of a yield from keyword, l.pos being
+// right after yield: whitespace and a from name, in any case. It returns
+// the end of the keyword. Only the whitespace and the name are looked at,
+// so scanning a run of yields stays linear.
 func tryScanYieldFrom(l *LexerScanner) (int, bool) {
-	foundTokenKind := false
-	foundPos := -1
-	from := "from"
-	fromLen := len(from)
-
-	for i := l.pos + 1; i < l.eofPos; i++ {
-
-		if unicode.IsSpace(rune(l.content[i])) || l.content[i] == ';' {
-			if foundTokenKind {
-				return i, true
-			}
-			continue
-		}
-
-		if i+fromLen > l.eofPos {
-			return -1, false
-		}
-
-		// no name start return false
-		if !isNameStart(l.content, i, l.eofPos) {
-			return -1, false
-		}
-
-		word := strings.ToLower(string(l.content[i : i+fromLen]))
-		if word == from {
-			foundTokenKind = true
-			i = i + fromLen - 1
-			foundPos = i + 1
-		}
+	pos := l.pos
+	for pos < l.eofPos && unicode.IsSpace(rune(l.content[pos])) {
+		pos++
 	}
-	return foundPos, foundTokenKind
+	if pos == l.pos || pos+4 > l.eofPos || !equalFoldASCII(l.content[pos:pos+4], "from") {
+		return -1, false
+	}
+	end := pos + 4
+	nameEnd := end
+	scanName(l.content, &nameEnd, l.eofPos)
+	if nameEnd != end {
+		// a longer name, like fromage
+		return -1, false
+	}
+	return end, true
 }
 
 func getNameOrDigitTokens(l *LexerScanner, tokenMem []*Token) (*Token, []*Token) {
 	if isNameStart(l.content, l.pos, l.eofPos) {
 		scanName(l.content, &l.pos, l.eofPos)
 		token := l.createToken(Name)
-		tokenText := token.getText(l.content)
-		lowerText := strings.ToLower(tokenText)
-		if isKeywordOrReservedWordStart(lowerText) {
-			token = getKeywordOrReservedWordTokenFromNameToken(token, lowerText, l.content, &l.pos, l.eofPos)
+		if kind, ok := lookupKeywordOrReservedWord(l.content[l.start:l.pos]); ok {
+			token.Kind = kind
 			if token.Kind == YieldKeyword {
 				newPos, ok := tryScanYieldFrom(l)
 				if ok {
@@ -534,13 +679,22 @@ func getNameOrDigitTokens(l *LexerScanner, tokenMem []*Token) (*Token, []*Token)
 		}
 		return token, tokenMem
 	} else if isDigitChar(rune(l.content[l.pos])) {
-		kind := scanNumericLiteral(l.content, &l.pos, l.eofPos)
-		return l.createToken(kind), tokenMem
+		return l.scanNumericLiteralToken(), tokenMem
 	}
-	l.pos++
+	_, size := utf8.DecodeRune(l.content[l.pos:])
+	l.pos += size
+	l.addDiagnostic(DiagnosticUnexpectedCharacter, l.start, l.pos, "Unexpected character in input")
 	return l.createToken(Unknown), tokenMem
 }
 
+func (l *LexerScanner) scanNumericLiteralToken() *Token {
+	kind, isValid := scanNumericLiteral(l.content, &l.pos, l.eofPos)
+	if !isValid {
+		l.addDiagnostic(DiagnosticInvalidNumericLiteral, l.start, l.pos, "Invalid numeric literal")
+	}
+	return l.createToken(kind)
+}
+
 func getStringQuoteTokens(l *LexerScanner, tokenMem []*Token) (*Token, []*Token) {
 	if l.content[l.pos] == '"' || l.content[l.pos] == '`' {
 		l.stringDelimiter = DoubleQuoteToken
@@ -554,23 +708,27 @@ func getStringQuoteTokens(l *LexerScanner, tokenMem []*Token) (*Token, []*Token)
 	if scanStringLiteral(l.content, &l.pos, l.eofPos) {
 		return l.createToken(StringLiteralToken), tokenMem
 	}
+	l.addDiagnostic(DiagnosticUnterminatedString, l.start, l.pos, "Unterminated string literal, ' expected")
 	return l.createToken(EncapsedAndWhitespace), tokenMem
 }
 
-func isScriptStartTag(text []byte, pos int, eofPos int) bool {
+func isScriptStartTag(text []byte, pos int, eofPos int, shortOpenTag bool) bool {
 
 	if text[pos] != '<' {
 		return false
 	}
 
+	if shortOpenTag && pos+1 < eofPos && text[pos+1] == '?' {
+		return true
+	}
+
 	if pos+3 > eofPos {
 		return false
 	}
 
 	if pos+5 < eofPos {
-		start := strings.ToLower(string(text[pos : 5+pos]))
 		end := text[pos+5]
-		if start == "<?php" {
+		if equalFoldASCII(text[pos:pos+5], "<?php") {
 			switch end {
 			case '\n',
 				'\r',
@@ -588,15 +746,15 @@ func isScriptStartTag(text []byte, pos int, eofPos int) bool {
 }
 
 func scanOperatorOrPunctuactorToken(lexer *LexerScanner) *Token {
-	// TODO this can be made more performant, but we're going for simple/correct first.
-	// TODO
-	for tokenEnd := 6; tokenEnd >= 0; tokenEnd-- {
+	// only the lengths of the operators starting with the current char are
+	// tried, longest first, and the text is lowercased without allocating.
+	var buf [maxOperatorsLength]byte
+	for tokenEnd := maxOperatorLength[lexer.content[lexer.pos]] - 1; tokenEnd >= 0; tokenEnd-- {
 		if lexer.pos+tokenEnd >= lexer.eofPos {
 			continue
 		}
-		// TODO get rid of strtolower for perf reasons
-		textSubstring := strings.ToLower(string(lexer.content[lexer.pos : lexer.pos+tokenEnd+1]))
-		if tokenKind, ok := OPERATORS_AND_PUNCTUATORS[textSubstring]; ok {
+		textSubstring := lowerASCII(buf[:0], lexer.content[lexer.pos:lexer.pos+tokenEnd+1])
+		if tokenKind, ok := OPERATORS_AND_PUNCTUATORS[string(textSubstring)]; ok {
 			if tokenKind == ScriptSectionStartTag {
 				if lexer.state == LexStateScriptSectionParsed {
 					continue
@@ -609,18 +767,53 @@ func scanOperatorOrPunctuactorToken(lexer *LexerScanner) *Token {
 			}
 			return lexer.createToken(tokenKind)
 		}
+		// <? is only a start tag when short_open_tag is enabled
+		if tokenEnd == 1 && string(textSubstring) == "<?" && lexer.options.ShortOpenTag && lexer.state == LexStateScriptSection {
+			lexer.state = LexStateScriptSectionParsed
+			lexer.pos += 2
+			return lexer.createToken(ScriptSectionStartTag)
+		}
 	}
 	panic("Unknown token Kind in OPERATORS_AND_PUNCTUATORS")
 }
 
-func getKeywordOrReservedWordTokenFromNameToken(token *Token, lowerKeywordStart string, text []byte, pos *int, eofPos int) *Token {
+// lookupKeywordOrReservedWord returns the kind of a name if it is a keyword
+// or a reserved word, keywords are case insensitive.
+func lookupKeywordOrReservedWord(name []byte) (TokenKind, bool) {
+	if len(name) > maxKeywordLength {
+		return Unknown, false
+	}
+	var buf [32]byte
+	kind, ok := keywordsAndReservedWords[string(lowerASCII(buf[:0], name))]
+	return kind, ok
+}
 
-	kind, ok := KEYWORDS[lowerKeywordStart]
-	if !ok {
-		kind, ok = RESERVED_WORDS[lowerKeywordStart]
+// lowerASCII appends text to dst with its ASCII letters lowercased.
+func lowerASCII(dst []byte, text []byte) []byte {
+	for _, char := range text {
+		if char >= 'A' && char <= 'Z' {
+			char += 'a' - 'A'
+		}
+		dst = append(dst, char)
 	}
-	token.Kind = kind
-	return token
+	return dst
+}
+
+// equalFoldASCII reports whether text is equal to the lowercase string
+// lower ignoring the case of ASCII letters.
+func equalFoldASCII(text []byte, lower string) bool {
+	if len(text) != len(lower) {
+		return false
+	}
+	for i, char := range text {
+		if char >= 'A' && char <= 'Z' {
+			char += 'a' - 'A'
+		}
+		if char != lower[i] {
+			return false
+		}
+	}
+	return true
 }
 
 func isDigitChar(at rune) bool {
@@ -628,16 +821,10 @@ func isDigitChar(at rune) bool {
 		at <= '9'
 }
 
-func isKeywordOrReservedWordStart(text string) bool {
-	_, ok := KEYWORDS[text]
-	_, ok2 := RESERVED_WORDS[text]
-	return ok || ok2
-}
-
 func scanStringLiteral(text []byte, pos *int, eofPos int) bool {
 	isTerminated := false
 	for *pos < eofPos {
-		if isSingleQuoteEscapeSequence(text, *pos) {
+		if *pos+1 < eofPos && isSingleQuoteEscapeSequence(text, *pos) {
 			*pos += 2
 			continue
 		} else if text[*pos] == '\'' {
@@ -653,15 +840,17 @@ func scanStringLiteral(text []byte, pos *int, eofPos int) bool {
 	return isTerminated
 }
 
-func scanDelimitedComment(text []byte, pos *int, eofPos int) {
+// scanDelimitedComment scans past the closing */ of a comment whose
+// opening /* was already consumed and reports if it was found.
+func scanDelimitedComment(text []byte, pos *int, eofPos int) bool {
 	for *pos < eofPos {
 		if *pos+1 < eofPos && text[*pos] == '*' && text[*pos+1] == '/' {
 			*pos += 2
-			return
+			return true
 		}
 		*pos++
 	}
-
+	return false
 }
 
 func scanName(text []byte, pos *int, eofPos int) {
@@ -683,17 +872,20 @@ func scanTemplateAndSetTokenValue(l *LexerScanner, tokenMem []*Token) []*Token {
 	*pos++
 	for {
 		if *pos >= eofPos {
-			// UNTERMINATED, report error
+			l.addDiagnostic(DiagnosticUnterminatedString, startPosition, *pos, "Unterminated string literal, "+string(fileContent[startPosition])+" expected")
 			if len(tokenMem) == 0 {
-				tokenMem = append(tokenMem, &Token{l.stringDelimiter, l.fullStart, l.start, l.start - l.fullStart + 1, TokenCatNormal})
+				tokenMem = append(tokenMem, &Token{l.stringDelimiter, l.fullStart, l.start, l.start - l.fullStart + 1, TokenCatNormal, nil})
 				l.start++
 				l.fullStart = l.start
 				if l.start != eofPos {
-					tokenMem = append(tokenMem, &Token{EncapsedAndWhitespace, l.fullStart, l.start, *pos - l.fullStart, TokenCatNormal})
+					tokenMem = append(tokenMem, &Token{EncapsedAndWhitespace, l.fullStart, l.start, *pos - l.fullStart, TokenCatNormal, nil})
 				}
 
 				return tokenMem
 			} else {
+				if *pos-l.fullStart > 0 {
+					tokenMem = l.addToMem(EncapsedAndWhitespace, *pos, tokenMem)
+				}
 				return tokenMem
 			}
 		}
@@ -719,7 +911,7 @@ func scanTemplateAndSetTokenValue(l *LexerScanner, tokenMem []*Token) []*Token {
 		if char == '$' {
 			if isNameStart(fileContent, *pos+1, eofPos) {
 				if len(tokenMem) == 0 {
-					tokenMem = append(tokenMem, &Token{l.stringDelimiter, l.fullStart, startPosition, startPosition - l.fullStart + 1, TokenCatNormal})
+					tokenMem = append(tokenMem, &Token{l.stringDelimiter, l.fullStart, startPosition, startPosition - l.fullStart + 1, TokenCatNormal, nil})
 					l.start++
 					l.fullStart = l.start
 				}
@@ -733,7 +925,7 @@ func scanTemplateAndSetTokenValue(l *LexerScanner, tokenMem []*Token) []*Token {
 				if *pos < eofPos && fileContent[*pos] == '[' {
 					*pos++
 					tokenMem = l.addToMem(OpenBracketToken, *pos, tokenMem)
-					if isDigitChar(rune(fileContent[*pos])) {
+					if *pos < eofPos && isDigitChar(rune(fileContent[*pos])) {
 						*pos++
 						scanName(fileContent, pos, eofPos)
 						tokenMem = l.addToMem(IntegerLiteralToken, *pos, tokenMem)
@@ -743,7 +935,7 @@ func scanTemplateAndSetTokenValue(l *LexerScanner, tokenMem []*Token) []*Token {
 						scanName(fileContent, pos, eofPos)
 						tokenMem = l.addToMem(Name, *pos, tokenMem)
 					}
-					if fileContent[*pos] == ']' {
+					if *pos < eofPos && fileContent[*pos] == ']' {
 						*pos++
 						tokenMem = l.addToMem(CloseBracketToken, *pos, tokenMem)
 					}
@@ -783,7 +975,7 @@ func scanTemplateAndSetTokenValue(l *LexerScanner, tokenMem []*Token) []*Token {
 		// Escape character
 		if char == '\\' {
 			*pos++
-			scanDqEscapeSequence(fileContent, pos, eofPos)
+			l.scanDqEscapeSequence(pos)
 			continue
 		}
 
@@ -794,7 +986,7 @@ func scanTemplateAndSetTokenValue(l *LexerScanner, tokenMem []*Token) []*Token {
 
 func saveCurlyExpression(l *LexerScanner, openToken TokenKind, pos *int, startPosition int, tokenMem []*Token) (bool, []*Token) {
 	if len(tokenMem) == 0 {
-		tokenMem = append(tokenMem, &Token{l.stringDelimiter, l.fullStart, startPosition, startPosition - l.fullStart + 1, TokenCatNormal})
+		tokenMem = append(tokenMem, &Token{l.stringDelimiter, l.fullStart, startPosition, startPosition - l.fullStart + 1, TokenCatNormal, nil})
 		l.start++
 		l.fullStart = l.start
 	}
@@ -883,9 +1075,18 @@ func saveCurlyExpressionHereDoc(l *LexerScanner, openToken TokenKind, pos *int,
 	return false, tokenMem
 }
 
-func scanDqEscapeSequence(text []byte, pos *int, eofPos int) {
+func (l *LexerScanner) scanDqEscapeSequence(pos *int) {
+	escapeStart := *pos - 1
+	if !scanDqEscapeSequence(l.content, pos, l.eofPos) {
+		l.addDiagnostic(DiagnosticInvalidEscapeSequence, escapeStart, *pos, "Invalid UTF-8 codepoint escape sequence")
+	}
+}
+
+// scanDqEscapeSequence scans an escape sequence after its backslash. Unknown
+// sequences are kept as is, only a malformed \u{...} is reported as invalid.
+func scanDqEscapeSequence(text []byte, pos *int, eofPos int) bool {
 	if *pos >= eofPos {
-		return
+		return true
 	}
 	char := text[*pos]
 	switch char {
@@ -899,45 +1100,47 @@ func scanDqEscapeSequence(text []byte, pos *int, eofPos int) {
 		't',
 		'v':
 		*pos++
-		return
+		return true
 
 		// dq-hexadecimal-escape-sequence
 	case 'x',
 		'X':
 		*pos++
 		for i := 0; i < 2; i++ {
-			if isHexadecimalDigit(rune(text[*pos])) {
+			if *pos < eofPos && isHexadecimalDigit(rune(text[*pos])) {
 				*pos++
 			}
 		}
-		return
+		return true
 
 		// dq-unicode-escape-sequence
 	case 'u':
 		*pos++
-		if text[*pos] == '{' {
+		if *pos < eofPos && text[*pos] == '{' {
+			*pos++
+			digitsStart := *pos
 			scanHexadecimalLiteral(text, pos, eofPos)
-			if text[*pos] == '}' {
+			if *pos < eofPos && text[*pos] == '}' && *pos > digitsStart {
 				*pos++
-				return
+				return true
 			}
-			// OTHERWISE ERROR
+			return false
 		}
-		return
+		return true
 	default:
 		// dq-octal-digit-escape-sequence
 		if isOctalDigitChar(rune(text[*pos])) {
 			for i := *pos; i < *pos+3; i++ {
 				if isOctalDigitChar(rune(text[*pos])) {
-					return
+					return true
 				}
 				*pos++
-				return
+				return true
 			}
 		}
 
 		*pos++
-		return
+		return true
 	}
 }
 
@@ -986,7 +1189,7 @@ func isSingleQuoteEscapeSequence(text []byte, pos int) bool {
 }
 
 func isScriptEndTag(text []byte, pos int, state LexerState) bool {
-	if state != LexStateScriptSection && text[pos] == '?' && text[pos+1] == '>' {
+	if state != LexStateScriptSection && pos+1 < len(text) && text[pos] == '?' && text[pos+1] == '>' {
 		return true
 	}
 	return false
@@ -1027,6 +1230,10 @@ func isNonDigitChar(charCode rune) bool {
 		'+', ')', '(':
 		return false
 	}
+	if charCode < ' ' || charCode == '\u007f' {
+		// control bytes are never part of a name
+		return false
+	}
 
 	return (charCode >= '\u0080' || charCode <= '\u00ff') &&
 		!unicode.IsSpace(charCode) &&
@@ -1043,18 +1250,13 @@ func isValidNameUnicodeChar(charCode rune) bool {
 
 func scanHexadecimalLiteral(text []byte, pos *int, eofPos int) bool {
 	isValid := true
-	p := *pos
-	for p < eofPos {
+	for *pos < eofPos {
 		charCode := rune(text[*pos])
 		if isHexadecimalDigit(charCode) {
-			p++
 			*pos++
 			continue
 		} else if isDigitChar(charCode) || isNameNonDigitChar(charCode) {
-			// REPORT ERROR;
-			p++
 			isValid = false
-			continue
 		}
 		break
 	}
@@ -1150,7 +1352,8 @@ func isBinaryLiteralStart(text []byte, pos int, eofPos int) bool {
 	return pos+1 < eofPos && text[pos] == '0' && (text[pos+1] == 'b' || text[pos+1] == 'B')
 }
 
-func scanNumericLiteral(text []byte, pos *int, eofPos int) TokenKind {
+// scanNumericLiteral scans a numeric literal and reports if it is well formed.
+func scanNumericLiteral(text []byte, pos *int, eofPos int) (TokenKind, bool) {
 	var prevPos int
 
 	if isBinaryLiteralStart(text, *pos, eofPos) {
@@ -1159,26 +1362,26 @@ func scanNumericLiteral(text []byte, pos *int, eofPos int) TokenKind {
 		isValidBinaryLiteral := scanBinaryLiteral(text, pos, eofPos)
 		if prevPos == *pos || !isValidBinaryLiteral {
 			// invalid binary literal
-			return IntegerLiteralToken
+			return IntegerLiteralToken, false
 		}
-		return IntegerLiteralToken
+		return IntegerLiteralToken, true
 		//return BinaryLiteralToken
 	} else if isHexadecimalLiteralStart(text, *pos, eofPos) {
 		*pos += 2
-
+		prevPos = *pos
 		isValidHexLiteral := scanHexadecimalLiteral(text, pos, eofPos)
-		if !isValidHexLiteral {
-			return IntegerLiteralToken
+		if prevPos == *pos || !isValidHexLiteral {
 			// invalid hexadecimal literal
+			return IntegerLiteralToken, false
 		}
-		return IntegerLiteralToken
+		return IntegerLiteralToken, true
 		//return HexadecimalLiteralToken
 	} else if isDigitChar(rune(text[*pos])) || text[*pos] == '.' {
 		// TODO throw error if there is no number past the dot.
 		prevPos = *pos
 		isValidFloatingLiteral := scanFloatingPointLiteral(text, pos, eofPos)
 		if isValidFloatingLiteral {
-			return FloatingLiteralToken
+			return FloatingLiteralToken, true
 		}
 
 		// Reset, try scanning octal literal
@@ -1188,19 +1391,19 @@ func scanNumericLiteral(text []byte, pos *int, eofPos int) TokenKind {
 
 			// Check that it's not a 0 decimal literal
 			if *pos == prevPos+1 {
-				return IntegerLiteralToken
+				return IntegerLiteralToken, true
 			}
 
 			if !isValidOctalLiteral {
-				return InvalidOctalLiteralToken
+				return InvalidOctalLiteralToken, false
 			}
-			return IntegerLiteralToken
+			return IntegerLiteralToken, true
 			//return OctalLiteralToken
 		}
 
 		scanDecimalLiteral(text, pos, eofPos)
-		return IntegerLiteralToken
+		return IntegerLiteralToken, true
 	}
 
-	return Unknown
+	return Unknown, false
 }
@@ -1,18 +1,28 @@
 package lexer
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
+	"flag"
 	"fmt"
 	diff "github.com/yudai/gojsondiff"
 	"github.com/yudai/gojsondiff/formatter"
 	"io/ioutil"
+	"math/rand"
 	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
 )
 
+// BenchmarkComplex reads complex.php, a synthetic file written for the
+// benchmarks, not code from a real project.
 func BenchmarkComplex(b *testing.B) {
 	data, _ := ioutil.ReadFile("cases/complex.php")
 	stream := TokensStream{}
+	b.SetBytes(int64(len(data)))
+	b.ReportAllocs()
 
 	for n := 0; n < b.N; n++ {
 		stream.Source(data)
@@ -20,6 +30,102 @@ func BenchmarkComplex(b *testing.B) {
 	}
 }
 
+// BenchmarkDebug reads debug/debug.php, the script generating the mstpp
+// goldens, as a real file written for use rather than for the benchmarks.
+func BenchmarkDebug(b *testing.B) {
+	data, _ := ioutil.ReadFile("../debug/debug.php")
+	stream := TokensStream{}
+	b.SetBytes(int64(len(data)))
+	b.ReportAllocs()
+
+	for n := 0; n < b.N; n++ {
+		stream.Source(data)
+		stream.CreateTokens()
+	}
+}
+
+// BenchmarkCorpus lexes every file of the cases directory, mostly small
+// files where the per-file setup cost weighs more.
+func BenchmarkCorpus(b *testing.B) {
+	files, _ := filepath.Glob("cases/*.php")
+	var sources [][]byte
+	size := 0
+	for _, file := range files {
+		data, _ := ioutil.ReadFile(file)
+		sources = append(sources, data)
+		size += len(data)
+	}
+	b.SetBytes(int64(size))
+	b.ReportAllocs()
+
+	for n := 0; n < b.N; n++ {
+		for _, data := range sources {
+			stream := TokensStream{}
+			stream.Source(data)
+			stream.CreateTokens()
+		}
+	}
+}
+
+// projectSize is the size of the project of BenchmarkProject, about the
+// size of a mid-sized php project.
+const projectSize = 10 << 20
+
+// project returns the files of BenchmarkProject: copies of complex.php,
+// debug/debug.php and the nikic cases, from 0.5KB to 15KB each, until
+// projectSize bytes. The repository holds no bigger real-world code.
+func project() ([][]byte, int) {
+	names, _ := filepath.Glob("../nikic/cases/*.php")
+	names = append(names, "cases/complex.php", "../debug/debug.php")
+	var sources, files [][]byte
+	for _, name := range names {
+		data, _ := ioutil.ReadFile(name)
+		sources = append(sources, data)
+	}
+	size := 0
+	for i := 0; size < projectSize; i++ {
+		data := sources[i%len(sources)]
+		files = append(files, data)
+		size += len(data)
+	}
+	return files, size
+}
+
+// BenchmarkProject lexes every file of a project of projectSize bytes, the
+// throughput to compare with the targets of the README.
+func BenchmarkProject(b *testing.B) {
+	files, size := project()
+	stream := TokensStream{}
+	b.SetBytes(int64(size))
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for n := 0; n < b.N; n++ {
+		for _, data := range files {
+			stream.Source(data)
+			stream.CreateTokens()
+		}
+	}
+}
+
+var targets = flag.Bool("targets", false, "check the throughput targets of the README")
+
+// TestTargets runs BenchmarkProject and fails under the lexer throughput
+// target of the README. It only runs with -targets, the numbers depend on
+// the machine.
+func TestTargets(t *testing.T) {
+	if !*targets {
+		t.Skip("run with -targets")
+	}
+	const target = 15 << 20 // bytes per second
+	result := testing.Benchmark(BenchmarkProject)
+	throughput := float64(result.Bytes*int64(result.N)) / result.T.Seconds()
+	t.Logf("%.1f MB/s", throughput/(1<<20))
+	if throughput < target {
+		t.Errorf("lexing the project at %.1f MB/s, the target is %d MB/s", throughput/(1<<20), target>>20)
+	}
+}
+
 func TestEx(t *testing.T) {
 
 	test := isNameNonDigitChar('√')
@@ -91,3 +197,399 @@ func TestCases(t *testing.T) {
 
 	}
 }
+
+func TestTokenGetAll(t *testing.T) {
+	cases := []struct {
+		source   string
+		expected string
+	}{
+		{
+			"<?php\n$a = 1;",
+			`[["T_OPEN_TAG","<?php\n",1],["T_VARIABLE","$a",2],["T_WHITESPACE"," ",2],"=",["T_WHITESPACE"," ",2],["T_LNUMBER","1",2],";"]`,
+		},
+		{
+			"<?php\n/** doc */\n// line\n# hash\n",
+			`[["T_OPEN_TAG","<?php\n",1],["T_DOC_COMMENT","/** doc */",2],["T_WHITESPACE","\n",2],["T_COMMENT","// line\n",3],["T_COMMENT","# hash\n",4]]`,
+		},
+		{
+			"<?php echo \"a $b[0] {$c}\";",
+			`[["T_OPEN_TAG","<?php ",1],["T_ECHO","echo",1],["T_WHITESPACE"," ",1],"\"",["T_ENCAPSED_AND_WHITESPACE","a ",1],["T_VARIABLE","$b",1],"[",["T_NUM_STRING","0",1],"]",["T_ENCAPSED_AND_WHITESPACE"," ",1],["T_CURLY_OPEN","{",1],["T_VARIABLE","$c",1],"}","\"",";"]`,
+		},
+		{
+			"<?php (int) __LINE__ <=> 9223372036854775808 ?>\nhtml",
+			`[["T_OPEN_TAG","<?php ",1],["T_INT_CAST","(int)",1],["T_WHITESPACE"," ",1],["T_LINE","__LINE__",1],["T_WHITESPACE"," ",1],["T_SPACESHIP","<=>",1],["T_WHITESPACE"," ",1],["T_DNUMBER","9223372036854775808",1],["T_WHITESPACE"," ",1],["T_CLOSE_TAG","?>\n",1],["T_INLINE_HTML","html",2]]`,
+		},
+		{
+			"<?php 0x7FFFFFFFFFFFFFFF 0x8000000000000000 0777777777777777777777 01000000000000000000000 0b111111111111111111111111111111111111111111111111111111111111111 0b1000000000000000000000000000000000000000000000000000000000000000",
+			`[["T_OPEN_TAG","<?php ",1],["T_LNUMBER","0x7FFFFFFFFFFFFFFF",1],["T_WHITESPACE"," ",1],["T_DNUMBER","0x8000000000000000",1],["T_WHITESPACE"," ",1],["T_LNUMBER","0777777777777777777777",1],["T_WHITESPACE"," ",1],["T_DNUMBER","01000000000000000000000",1],["T_WHITESPACE"," ",1],["T_LNUMBER","0b111111111111111111111111111111111111111111111111111111111111111",1],["T_WHITESPACE"," ",1],["T_DNUMBER","0b1000000000000000000000000000000000000000000000000000000000000000",1]]`,
+		},
+		{
+			"<?php $a ??= 1; $a ?? = 1;",
+			`[["T_OPEN_TAG","<?php ",1],["T_VARIABLE","$a",1],["T_WHITESPACE"," ",1],["T_COALESCE_EQUAL","??=",1],["T_WHITESPACE"," ",1],["T_LNUMBER","1",1],";",["T_WHITESPACE"," ",1],["T_VARIABLE","$a",1],["T_WHITESPACE"," ",1],["T_COALESCE","??",1],["T_WHITESPACE"," ",1],"=",["T_WHITESPACE"," ",1],["T_LNUMBER","1",1],";"]`,
+		},
+		{
+			"a<?= $b ?>",
+			`[["T_INLINE_HTML","a",1],["T_OPEN_TAG_WITH_ECHO","<?=",1],["T_WHITESPACE"," ",1],["T_VARIABLE","$b",1],["T_WHITESPACE"," ",1],["T_CLOSE_TAG","?>",1]]`,
+		},
+	}
+	for _, c := range cases {
+		buffer := &bytes.Buffer{}
+		encoder := json.NewEncoder(buffer)
+		encoder.SetEscapeHTML(false)
+		encoder.Encode(TokenGetAll([]byte(c.source)))
+		if got := strings.TrimSpace(buffer.String()); got != c.expected {
+			t.Errorf("TokenGetAll(%q)\n got: %s\nwant: %s", c.source, got, c.expected)
+		}
+	}
+}
+
+// TestPhpTokenNames checks that every kind the lexer emits has a PHP name,
+// or is a one character punctuation.
+func TestPhpTokenNames(t *testing.T) {
+	named := func(kind TokenKind, text string) {
+		if _, ok := PHP_TOKEN_NAMES[kind]; !ok && len(text) != 1 {
+			t.Errorf("no PHP name for %s %q", kind, text)
+		}
+	}
+	for _, words := range []map[string]TokenKind{KEYWORDS, RESERVED_WORDS, OPERATORS_AND_PUNCTUATORS} {
+		for word, kind := range words {
+			named(kind, word)
+		}
+	}
+	sourceFiles, _ := filepath.Glob("cases/*.php")
+	for _, sourceFile := range sourceFiles {
+		source, _ := ioutil.ReadFile(sourceFile)
+		stream := TokensStream{}
+		stream.Source(source)
+		stream.CreateTokens()
+		for _, token := range stream.Tokens[:stream.EofPos] {
+			named(token.Kind, token.Text(source))
+		}
+	}
+}
+
+func TestTokenGetAllReproducesSource(t *testing.T) {
+	sourceFiles, _ := filepath.Glob("cases/*.php")
+	for _, sourceFile := range sourceFiles {
+		source, _ := ioutil.ReadFile(sourceFile)
+		text := ""
+		for _, token := range TokenGetAll(source) {
+			text += token.Text
+		}
+		if text != string(source) {
+			t.Errorf("%s: token texts do not add up to the source", sourceFile)
+		}
+	}
+}
+
+func TestDiagnostics(t *testing.T) {
+	cases := []struct {
+		source   string
+		expected []Diagnostic
+	}{
+		{"<?php $a = 1;", nil},
+		{"<?php /* open", []Diagnostic{{DiagnosticUnterminatedComment, 6, 7, "Unterminated comment starts here"}}},
+		{"<?php 'abc", []Diagnostic{{DiagnosticUnterminatedString, 6, 4, "Unterminated string literal, ' expected"}}},
+		{"<?php \"a $b", []Diagnostic{{DiagnosticUnterminatedString, 6, 5, "Unterminated string literal, \" expected"}}},
+		{"<?php `ls", []Diagnostic{{DiagnosticUnterminatedString, 6, 3, "Unterminated string literal, ` expected"}}},
+		{"<?php <<<EOT\nabc\n", []Diagnostic{{DiagnosticUnterminatedHeredoc, 13, 4, "Unterminated heredoc, 'EOT' expected"}}},
+		{"<?php <<<'EOT'\nabc\n", []Diagnostic{{DiagnosticUnterminatedHeredoc, 15, 4, "Unterminated nowdoc, 'EOT' expected"}}},
+		{"<?php \"\\u{zz}\";", []Diagnostic{{DiagnosticInvalidEscapeSequence, 7, 3, "Invalid UTF-8 codepoint escape sequence"}}},
+		{"<?php \"\\u{1F600}\\x41\\q\";", nil},
+		{"<?php 0789;", []Diagnostic{{DiagnosticInvalidNumericLiteral, 6, 4, "Invalid numeric literal"}}},
+		{"<?php 0x;", []Diagnostic{{DiagnosticInvalidNumericLiteral, 6, 2, "Invalid numeric literal"}}},
+		{"<?php 0b12;", []Diagnostic{{DiagnosticInvalidNumericLiteral, 6, 3, "Invalid numeric literal"}}},
+		{"<?php $a\x01b;", []Diagnostic{{DiagnosticUnexpectedCharacter, 8, 1, "Unexpected character in input"}}},
+	}
+	for _, c := range cases {
+		stream := TokensStream{}
+		stream.Source([]byte(c.source))
+		stream.CreateTokens()
+		if !reflect.DeepEqual(stream.Diagnostics, c.expected) {
+			t.Errorf("%q\n got: %v\nwant: %v", c.source, stream.Diagnostics, c.expected)
+		}
+	}
+}
+
+func TestShortOpenTag(t *testing.T) {
+	source := []byte("<p><? echo 1 ?></p><?= $a ?><?php\n")
+	expected := map[bool][]TokenKind{
+		false: {InlineHtml, ScriptSectionStartTag, VariableName, ScriptSectionEndTag, ScriptSectionStartTag, EndOfFileToken},
+		true:  {InlineHtml, ScriptSectionStartTag, EchoKeyword, IntegerLiteralToken, ScriptSectionEndTag, InlineHtml, ScriptSectionStartTag, VariableName, ScriptSectionEndTag, ScriptSectionStartTag, EndOfFileToken},
+	}
+	for shortOpenTag, kinds := range expected {
+		stream := TokensStream{Options: Options{ShortOpenTag: shortOpenTag}}
+		stream.Source(source)
+		stream.CreateTokens()
+		got := make([]TokenKind, 0, len(stream.Tokens))
+		for _, token := range stream.Tokens {
+			got = append(got, token.Kind)
+		}
+		if !reflect.DeepEqual(got, kinds) {
+			t.Errorf("ShortOpenTag=%v\n got: %v\nwant: %v", shortOpenTag, got, kinds)
+		}
+	}
+}
+
+func TestPrependedText(t *testing.T) {
+	cases := map[string][]TokenKind{
+		"#!/usr/bin/env php\n<?php\n": {ScriptSectionPrependedText, ScriptSectionStartTag, EndOfFileToken},
+		"\xEF\xBB\xBF<?php\n":         {ScriptSectionPrependedText, ScriptSectionStartTag, EndOfFileToken},
+		"\xEF\xBB\xBF#!php\nhtml":     {ScriptSectionPrependedText, InlineHtml, EndOfFileToken},
+		"html #!php\n<?php\n":         {InlineHtml, ScriptSectionStartTag, EndOfFileToken},
+		"#!/usr/bin/env php":          {ScriptSectionPrependedText, EndOfFileToken},
+	}
+	for source, kinds := range cases {
+		stream := TokensStream{}
+		stream.Source([]byte(source))
+		stream.CreateTokens()
+		got := make([]TokenKind, 0, len(stream.Tokens))
+		for _, token := range stream.Tokens {
+			got = append(got, token.Kind)
+		}
+		if !reflect.DeepEqual(got, kinds) {
+			t.Errorf("%q\n got: %v\nwant: %v", source, got, kinds)
+		}
+	}
+
+	if bom, length := DetectBOM([]byte("\xFF\xFE<\x00")); bom != UTF16LEBOM || length != 2 {
+		t.Errorf("expected UTF-16 LE mark, got %v %d", bom, length)
+	}
+	if bom, _ := DetectBOM([]byte("<?php")); bom != NoBOM {
+		t.Errorf("expected no mark, got %v", bom)
+	}
+}
+
+func TestTranscode(t *testing.T) {
+	source := []byte("<?php '\x80 caf\xE9'; $a\xFF;")
+	stream := TokensStream{Options: Options{Encoding: EncodingWindows1252}}
+	stream.Source(source)
+	stream.CreateTokens()
+
+	content := stream.Content()
+	if string(content) != "<?php '€ café'; $aÿ;" {
+		t.Errorf("unexpected transcoding %q", content)
+	}
+	variable := stream.Tokens[3]
+	if variable.Kind != VariableName || variable.Text(content) != "$aÿ" {
+		t.Errorf("unexpected token %v", variable)
+	}
+	// offsets after, at and inside transcoded chars
+	offsets := map[int]int{0: 0, 7: 7, 8: 7, 10: 8, 14: 12, 15: 12, 16: 13, 20: 17, 22: 18}
+	for offset, expected := range offsets {
+		if original := stream.Offsets.OriginalOffset(offset); original != expected {
+			t.Errorf("offset %d: got %d, want %d", offset, original, expected)
+		}
+	}
+
+	// token_get_all of a transcoded source, what gphp scan --format=php-tokens --encoding writes
+	if phpTokens := stream.PhpTokens(); phpTokens[1].Text != "'€ café'" {
+		t.Errorf("unexpected php token %v", phpTokens[1])
+	}
+
+	latin1, offsetMap := Transcode(source, EncodingLatin1)
+	if string(latin1) != "<?php '\u0080 café'; $aÿ;" || offsetMap == nil {
+		t.Errorf("unexpected transcoding %q", latin1)
+	}
+	if utf8Source, offsetMap := Transcode([]byte("\xEF\xBB\xBFcaf\xC3\xA9"), EncodingLatin1); string(utf8Source) != "\xEF\xBB\xBFcafé" || offsetMap != nil {
+		t.Errorf("sources with a UTF-8 byte order mark must not be transcoded")
+	}
+}
+
+func TestTokenValues(t *testing.T) {
+	source := []byte("<?php 42 0x1F 0b101 0777 1.5e3 9223372036854775808 'it\\'s \\\\ \\n' b\"tab\\t \\x41\\101 \\u{1F600} \\$ \\q\"; <<<'EOT'\nraw \\n\nEOT;\n")
+	stream := TokensStream{}
+	stream.Source(source)
+	stream.CreateTokens()
+	tokens := stream.Tokens
+
+	if tokens[2].Text(source) != "0x1F" || tokens[2].FullText(source) != " 0x1F" || tokens[2].LeadingTrivia(source) != " " {
+		t.Errorf("unexpected texts for %v", tokens[2])
+	}
+
+	integers := []int64{42, 31, 5, 511}
+	for i, expected := range integers {
+		value, err := tokens[1+i].IntegerValue(source)
+		if err != nil || value != expected {
+			t.Errorf("IntegerValue(%q) = %d, %v; want %d", tokens[1+i].Text(source), value, err, expected)
+		}
+	}
+	if value, err := tokens[5].FloatValue(source); err != nil || value != 1500 {
+		t.Errorf("FloatValue(1.5e3) = %v, %v", value, err)
+	}
+	if _, err := tokens[6].IntegerValue(source); err == nil {
+		t.Errorf("expected an overflow error")
+	}
+	if value, err := tokens[6].FloatValue(source); err != nil || value != 9223372036854775808 {
+		t.Errorf("FloatValue(9223372036854775808) = %v, %v", value, err)
+	}
+	if value, err := tokens[7].StringValue(source); err != nil || value != "it's \\ \\n" {
+		t.Errorf("StringValue(%q) = %q, %v", tokens[7].Text(source), value, err)
+	}
+	if value, err := tokens[8].StringValue(source); err != nil || value != "tab\t AA 😀 $ \\q" {
+		t.Errorf("StringValue(%q) = %q, %v", tokens[8].Text(source), value, err)
+	}
+	if tokens[10].Kind != HeredocStart {
+		t.Fatalf("expected a nowdoc, got %v", tokens[10].Kind)
+	}
+	if value, err := tokens[11].NowdocValue(source); err != nil || value != "raw \\n" {
+		t.Errorf("NowdocValue(%q) = %q, %v", tokens[11].Text(source), value, err)
+	}
+	heredoc := []byte("<?php <<<EOT\nraw \\n\nEOT;\n")
+	stream = TokensStream{}
+	stream.Source(heredoc)
+	stream.CreateTokens()
+	if body := stream.Tokens[2]; body.Kind != EncapsedAndWhitespace {
+		t.Errorf("expected a heredoc body, got %v", body.Kind)
+	} else if _, err := body.NowdocValue(heredoc); err != ErrNotStringLiteral {
+		t.Errorf("NowdocValue of a heredoc body: expected ErrNotStringLiteral, got %v", err)
+	}
+	if _, err := UnescapeDoubleQuoted("\\u{zz}"); err != ErrInvalidEscapeSequence {
+		t.Errorf("expected an invalid escape sequence error, got %v", err)
+	}
+	if value, _ := UnescapeHeredoc("say \\\"hi\\\""); value != "say \\\"hi\\\"" {
+		t.Errorf("UnescapeHeredoc unescaped a double quote: %q", value)
+	}
+}
+
+var editSnippets = []string{
+	"", " ", "\n", "'", "\"", "`", "$", "{", "}", "(", ")", ";", "/*", "*/", "//", "#",
+	"?>", "<?php ", "<?=", "<<<EOT\n", "\nEOT;\n", "<<<'EOT'\n", "${", "{$", "\\",
+	"(int)", "( string )", "yield", " from ", "0x", "1.5e", "$a->b", "\"$a[0]\"", "abc",
+}
+
+func TestApplyEdit(t *testing.T) {
+	sourceFiles, _ := filepath.Glob("cases/*.php")
+	random := rand.New(rand.NewSource(1))
+	for _, sourceFile := range sourceFiles {
+		source, _ := ioutil.ReadFile(sourceFile)
+		stream := TokensStream{}
+		stream.Source(source)
+		stream.CreateTokens()
+
+		for i := 0; i < 50; i++ {
+			start := random.Intn(len(source) + 1)
+			oldLength := random.Intn(11)
+			if start+oldLength > len(source) {
+				oldLength = len(source) - start
+			}
+			newText := []byte(editSnippets[random.Intn(len(editSnippets))])
+			if random.Intn(4) == 0 {
+				// move text around the file
+				from := random.Intn(len(source) + 1)
+				to := from + random.Intn(20)
+				if to > len(source) {
+					to = len(source)
+				}
+				newText = append([]byte{}, source[from:to]...)
+			}
+			source = append(append(append([]byte{}, source[:start]...), newText...), source[start+oldLength:]...)
+			stream.ApplyEdit(start, oldLength, newText)
+
+			expected := TokensStream{}
+			expected.Source(source)
+			expected.CreateTokens()
+			if !reflect.DeepEqual(stream.Serialize(), expected.Serialize()) || !reflect.DeepEqual(stream.Diagnostics, expected.Diagnostics) {
+				t.Fatalf("%s: edit %d at %d replacing %d bytes with %q differs from a full relex of:\n%s", sourceFile, i, start, oldLength, newText, source)
+			}
+		}
+	}
+}
+
+func TestKeywordLookup(t *testing.T) {
+	for text, kind := range KEYWORDS {
+		if got, ok := lookupKeywordOrReservedWord([]byte(strings.ToUpper(text))); !ok || got != kind {
+			t.Errorf("%s: got %v, want %v", text, got, kind)
+		}
+	}
+	if _, ok := lookupKeywordOrReservedWord([]byte("breaK")); ok {
+		t.Errorf("only ASCII letters are case folded")
+	}
+	for _, kinds := range [][]TokenKind{GetNameOrKeywordOrReservedWordTokens(), GetNameOrReservedWordTokens()} {
+		if kinds[0] != Name {
+			t.Errorf("Name must be the first kind, got %v", kinds[0])
+		}
+		for _, kind := range kinds {
+			if !IsNameOrKeywordOrReservedWordTokens(kind) {
+				t.Errorf("%v not in set", kind)
+			}
+		}
+	}
+	if IsKeywordToken(VariableName) || IsReservedWordToken(-1) {
+		t.Errorf("unexpected member")
+	}
+}
+
+func TestYieldFrom(t *testing.T) {
+	cases := map[string][]TokenKind{
+		"<?php yield from $a;":    {ScriptSectionStartTag, YieldFromKeyword, VariableName, SemicolonToken, EndOfFileToken},
+		"<?php yield\n\tFROM $a;": {ScriptSectionStartTag, YieldFromKeyword, VariableName, SemicolonToken, EndOfFileToken},
+		"<?php yield fromage;":    {ScriptSectionStartTag, YieldKeyword, Name, SemicolonToken, EndOfFileToken},
+		"<?php yield; from;":      {ScriptSectionStartTag, YieldKeyword, SemicolonToken, Name, SemicolonToken, EndOfFileToken},
+		"<?php yield $a from;":    {ScriptSectionStartTag, YieldKeyword, VariableName, Name, SemicolonToken, EndOfFileToken},
+	}
+	for source, expected := range cases {
+		stream := TokensStream{}
+		stream.Source([]byte(source))
+		stream.CreateTokens()
+		var kinds []TokenKind
+		for _, token := range stream.Tokens {
+			kinds = append(kinds, token.Kind)
+		}
+		if !reflect.DeepEqual(kinds, expected) {
+			t.Errorf("%q: got %v, want %v", source, kinds, expected)
+		}
+	}
+}
+
+func TestCreateTokensContext(t *testing.T) {
+	// yields are scanned in linear time
+	source := []byte("<?php " + strings.Repeat("yield ", 200000))
+	stream := TokensStream{}
+	stream.Source(source)
+	if err := stream.CreateTokensContext(context.Background(), 0); err != nil || len(stream.Tokens) != 200002 {
+		t.Fatalf("got %d tokens, %v", len(stream.Tokens), err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	stream = TokensStream{}
+	stream.Source(source)
+	if err := stream.CreateTokensContext(ctx, 0); err != context.Canceled {
+		t.Errorf("expected the scan to be canceled, got %v", err)
+	}
+	if eof := stream.Tokens[stream.EofPos]; eof.Kind != EndOfFileToken || len(stream.Tokens) > 2*checkContextEvery {
+		t.Errorf("expected the tokens to end after the cancellation, got %d tokens", len(stream.Tokens))
+	}
+
+	stream = TokensStream{}
+	stream.Source(source)
+	if err := stream.CreateTokensContext(context.Background(), 10); err != ErrMaxTokens || len(stream.Tokens) != 10 {
+		t.Errorf("expected 9 tokens and an end of file, got %d tokens, %v", len(stream.Tokens), err)
+	}
+	if eof := stream.Tokens[stream.EofPos]; eof.Kind != EndOfFileToken || eof.FullStart != 6+8*6-1 {
+		t.Errorf("unexpected end of file %+v", eof)
+	}
+
+	// the limit includes the end of file
+	stream = TokensStream{}
+	stream.Source([]byte("<?php yield yield"))
+	if err := stream.CreateTokensContext(context.Background(), 4); err != nil || len(stream.Tokens) != 4 {
+		t.Errorf("expected the source to fit in 4 tokens, got %d tokens, %v", len(stream.Tokens), err)
+	}
+	stream.Source([]byte("<?php yield yield"))
+	if err := stream.CreateTokensContext(context.Background(), 3); err != ErrMaxTokens || len(stream.Tokens) != 3 {
+		t.Errorf("expected the scan to stop at 3 tokens, got %d tokens, %v", len(stream.Tokens), err)
+	}
+}
+
+func TestSyntheticToken(t *testing.T) {
+	token := NewSyntheticToken(Name, "foo")
+	copied := *token
+	if token.Text(nil) != "foo" || token.FullText([]byte("bar")) != "foo" || token.LeadingTrivia(nil) != "" || copied.Text(nil) != "foo" {
+		t.Errorf("unexpected texts %q %q %q", token.Text(nil), token.FullText(nil), copied.Text(nil))
+	}
+	if other := NewSyntheticToken(Name, "quux"); other.Text(nil) != "quux" || other.Length != 4 {
+		t.Errorf("unexpected token %+v", other)
+	}
+}
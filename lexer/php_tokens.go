@@ -0,0 +1,340 @@
+package lexer
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+	"strings"
+)
+
+// PhpToken is a token in the shape produced by PHP's token_get_all:
+// the T_* name, the token text and the line where the token starts.
+// Tokens that PHP reports as plain one character strings (";", "{", ...)
+// have an empty Name.
+type PhpToken struct {
+	Name string
+	Text string
+	Line int
+}
+
+// MarshalJSON encodes the token like json_encode(token_get_all(...)) does,
+// using the T_* name instead of the numeric id.
+func (t PhpToken) MarshalJSON() ([]byte, error) {
+	var value interface{} = []interface{}{t.Name, t.Text, t.Line}
+	if t.Name == "" {
+		value = t.Text
+	}
+	buffer := &bytes.Buffer{}
+	encoder := json.NewEncoder(buffer)
+	encoder.SetEscapeHTML(false)
+	err := encoder.Encode(value)
+	return bytes.TrimRight(buffer.Bytes(), "\n"), err
+}
+
+// Token names follow PHP 7.4, the last version that tokenizes qualified
+// names and comments the same way this lexer does.
+var PHP_TOKEN_NAMES = map[TokenKind]string{
+	Name:         "T_STRING",
+	VariableName: "T_VARIABLE",
+
+	AbstractKeyword:    "T_ABSTRACT",
+	AndKeyword:         "T_LOGICAL_AND",
+	ArrayKeyword:       "T_ARRAY",
+	AsKeyword:          "T_AS",
+	BreakKeyword:       "T_BREAK",
+	CallableKeyword:    "T_CALLABLE",
+	CaseKeyword:        "T_CASE",
+	CatchKeyword:       "T_CATCH",
+	ClassKeyword:       "T_CLASS",
+	CloneKeyword:       "T_CLONE",
+	ConstKeyword:       "T_CONST",
+	ContinueKeyword:    "T_CONTINUE",
+	DeclareKeyword:     "T_DECLARE",
+	DefaultKeyword:     "T_DEFAULT",
+	DieKeyword:         "T_EXIT",
+	DoKeyword:          "T_DO",
+	EchoKeyword:        "T_ECHO",
+	ElseKeyword:        "T_ELSE",
+	ElseIfKeyword:      "T_ELSEIF",
+	EmptyKeyword:       "T_EMPTY",
+	EndDeclareKeyword:  "T_ENDDECLARE",
+	EndForKeyword:      "T_ENDFOR",
+	EndForEachKeyword:  "T_ENDFOREACH",
+	EndIfKeyword:       "T_ENDIF",
+	EndSwitchKeyword:   "T_ENDSWITCH",
+	EndWhileKeyword:    "T_ENDWHILE",
+	EvalKeyword:        "T_EVAL",
+	ExitKeyword:        "T_EXIT",
+	ExtendsKeyword:     "T_EXTENDS",
+	FinalKeyword:       "T_FINAL",
+	FinallyKeyword:     "T_FINALLY",
+	ForKeyword:         "T_FOR",
+	ForeachKeyword:     "T_FOREACH",
+	FunctionKeyword:    "T_FUNCTION",
+	GlobalKeyword:      "T_GLOBAL",
+	GotoKeyword:        "T_GOTO",
+	IfKeyword:          "T_IF",
+	ImplementsKeyword:  "T_IMPLEMENTS",
+	IncludeKeyword:     "T_INCLUDE",
+	IncludeOnceKeyword: "T_INCLUDE_ONCE",
+	InstanceOfKeyword:  "T_INSTANCEOF",
+	InsteadOfKeyword:   "T_INSTEADOF",
+	InterfaceKeyword:   "T_INTERFACE",
+	IsSetKeyword:       "T_ISSET",
+	ListKeyword:        "T_LIST",
+	NamespaceKeyword:   "T_NAMESPACE",
+	NewKeyword:         "T_NEW",
+	OrKeyword:          "T_LOGICAL_OR",
+	PrintKeyword:       "T_PRINT",
+	PrivateKeyword:     "T_PRIVATE",
+	ProtectedKeyword:   "T_PROTECTED",
+	PublicKeyword:      "T_PUBLIC",
+	RequireKeyword:     "T_REQUIRE",
+	RequireOnceKeyword: "T_REQUIRE_ONCE",
+	ReturnKeyword:      "T_RETURN",
+	StaticKeyword:      "T_STATIC",
+	SwitchKeyword:      "T_SWITCH",
+	ThrowKeyword:       "T_THROW",
+	TraitKeyword:       "T_TRAIT",
+	TryKeyword:         "T_TRY",
+	UnsetKeyword:       "T_UNSET",
+	UseKeyword:         "T_USE",
+	VarKeyword:         "T_VAR",
+	WhileKeyword:       "T_WHILE",
+	XorKeyword:         "T_LOGICAL_XOR",
+	YieldKeyword:       "T_YIELD",
+	YieldFromKeyword:   "T_YIELD_FROM",
+
+	ArrowToken:                        "T_OBJECT_OPERATOR",
+	DoubleArrowToken:                  "T_DOUBLE_ARROW",
+	PlusPlusToken:                     "T_INC",
+	MinusMinusToken:                   "T_DEC",
+	AsteriskAsteriskToken:             "T_POW",
+	LessThanLessThanToken:             "T_SL",
+	GreaterThanGreaterThanToken:       "T_SR",
+	LessThanEqualsToken:               "T_IS_SMALLER_OR_EQUAL",
+	GreaterThanEqualsToken:            "T_IS_GREATER_OR_EQUAL",
+	EqualsEqualsToken:                 "T_IS_EQUAL",
+	EqualsEqualsEqualsToken:           "T_IS_IDENTICAL",
+	ExclamationEqualsToken:            "T_IS_NOT_EQUAL",
+	LessThanGreaterThanToken:          "T_IS_NOT_EQUAL",
+	ExclamationEqualsEqualsToken:      "T_IS_NOT_IDENTICAL",
+	AmpersandAmpersandToken:           "T_BOOLEAN_AND",
+	BarBarToken:                       "T_BOOLEAN_OR",
+	AsteriskAsteriskEqualsToken:       "T_POW_EQUAL",
+	AsteriskEqualsToken:               "T_MUL_EQUAL",
+	SlashEqualsToken:                  "T_DIV_EQUAL",
+	PercentEqualsToken:                "T_MOD_EQUAL",
+	PlusEqualsToken:                   "T_PLUS_EQUAL",
+	MinusEqualsToken:                  "T_MINUS_EQUAL",
+	DotEqualsToken:                    "T_CONCAT_EQUAL",
+	LessThanLessThanEqualsToken:       "T_SL_EQUAL",
+	GreaterThanGreaterThanEqualsToken: "T_SR_EQUAL",
+	AmpersandEqualsToken:              "T_AND_EQUAL",
+	CaretEqualsToken:                  "T_XOR_EQUAL",
+	BarEqualsToken:                    "T_OR_EQUAL",
+	QuestionQuestionToken:             "T_COALESCE",
+	LessThanEqualsGreaterThanToken:    "T_SPACESHIP",
+	DotDotDotToken:                    "T_ELLIPSIS",
+	BackslashToken:                    "T_NS_SEPARATOR",
+	ColonColonToken:                   "T_DOUBLE_COLON",
+
+	IntegerLiteralToken:      "T_LNUMBER",
+	InvalidOctalLiteralToken: "T_LNUMBER",
+	FloatingLiteralToken:     "T_DNUMBER",
+	StringLiteralToken:       "T_CONSTANT_ENCAPSED_STRING",
+
+	IntReservedWord:     "T_STRING",
+	FloatReservedWord:   "T_STRING",
+	TrueReservedWord:    "T_STRING",
+	StringReservedWord:  "T_STRING",
+	BoolReservedWord:    "T_STRING",
+	NullReservedWord:    "T_STRING",
+	VoidReservedWord:    "T_STRING",
+	FalseReservedWord:   "T_STRING",
+	BinaryReservedWord:  "T_STRING",
+	BooleanReservedWord: "T_STRING",
+	DoubleReservedWord:  "T_STRING",
+	IntegerReservedWord: "T_STRING",
+	ObjectReservedWord:  "T_STRING",
+	RealReservedWord:    "T_STRING",
+
+	ScriptSectionStartTag:      "T_OPEN_TAG",
+	ScriptSectionEndTag:        "T_CLOSE_TAG",
+	ScriptSectionPrependedText: "T_INLINE_HTML",
+	InlineHtml:                 "T_INLINE_HTML",
+
+	EncapsedAndWhitespace: "T_ENCAPSED_AND_WHITESPACE",
+	DollarOpenBraceToken:  "T_DOLLAR_OPEN_CURLY_BRACES",
+	OpenBraceDollarToken:  "T_CURLY_OPEN",
+	HeredocStart:          "T_START_HEREDOC",
+	HeredocEnd:            "T_END_HEREDOC",
+	StringVarname:         "T_STRING_VARNAME",
+
+	UnsetCastToken:  "T_UNSET_CAST",
+	StringCastToken: "T_STRING_CAST",
+	ObjectCastToken: "T_OBJECT_CAST",
+	IntCastToken:    "T_INT_CAST",
+	DoubleCastToken: "T_DOUBLE_CAST",
+	BoolCastToken:   "T_BOOL_CAST",
+	ArrayCastToken:  "T_ARRAY_CAST",
+
+	Unknown: "T_BAD_CHARACTER",
+}
+
+var PHP_MAGIC_CONSTANTS = map[string]string{
+	"__class__":       "T_CLASS_C",
+	"__dir__":         "T_DIR",
+	"__file__":        "T_FILE",
+	"__function__":    "T_FUNC_C",
+	"__line__":        "T_LINE",
+	"__method__":      "T_METHOD_C",
+	"__namespace__":   "T_NS_C",
+	"__trait__":       "T_TRAIT_C",
+	"__halt_compiler": "T_HALT_COMPILER",
+}
+
+// TokenGetAll tokenizes source and returns the same token sequence that
+// token_get_all would: trivia is reported as T_WHITESPACE, T_COMMENT and
+// T_DOC_COMMENT tokens and the texts of all tokens add up to source.
+func TokenGetAll(source []byte) []PhpToken {
+	stream := TokensStream{}
+	stream.Source(source)
+	stream.CreateTokens()
+	return stream.PhpTokens()
+}
+
+// PhpTokens converts the scanned tokens to the token_get_all shape.
+func (s *TokensStream) PhpTokens() []PhpToken {
+	content := s.lexer.content
+	phpTokens := make([]PhpToken, 0, len(s.Tokens)*2)
+	line := 1
+	add := func(name string, text string) {
+		phpTokens = append(phpTokens, PhpToken{name, text, line})
+		line += countLines(text)
+	}
+
+	inString := false
+	curlyDepth := 0
+	for i := 0; i < len(s.Tokens); i++ {
+		token := s.Tokens[i]
+		for _, trivia := range splitTrivia(content, token.FullStart, token.Start) {
+			add(trivia.Name, trivia.Text)
+		}
+		if token.Kind == EndOfFileToken {
+			break
+		}
+		// the lexer has no ??= token, PHP 7.4 has one
+		if next := s.Tokens[i+1]; token.Kind == QuestionQuestionToken && next.Kind == EqualsToken && next.FullStart == token.FullStart+token.Length && next.Start == next.FullStart {
+			add("T_COALESCE_EQUAL", "??=")
+			i++
+			continue
+		}
+		text := token.Text(content)
+		add(phpTokenName(token.Kind, text, inString && curlyDepth == 0), text)
+
+		switch token.Kind {
+		case DoubleQuoteToken, BacktickToken:
+			inString = !inString
+		case HeredocStart:
+			inString = true
+		case HeredocEnd:
+			inString = false
+		case DollarOpenBraceToken, OpenBraceDollarToken:
+			if inString {
+				curlyDepth++
+			}
+		case OpenBraceToken:
+			if curlyDepth > 0 {
+				curlyDepth++
+			}
+		case CloseBraceToken:
+			if curlyDepth > 0 {
+				curlyDepth--
+			}
+		}
+	}
+	return phpTokens
+}
+
+func phpTokenName(kind TokenKind, text string, inStringOffset bool) string {
+	switch kind {
+	case Name:
+		if name, ok := PHP_MAGIC_CONSTANTS[strings.ToLower(text)]; ok {
+			return name
+		}
+	case ScriptSectionStartTag:
+		if text == "<?=" {
+			return "T_OPEN_TAG_WITH_ECHO"
+		}
+	case IntegerLiteralToken:
+		if inStringOffset {
+			return "T_NUM_STRING"
+		}
+		// integers that do not fit in a PHP int are floats, in any base
+		if _, err := strconv.ParseInt(text, 0, 64); err != nil && err.(*strconv.NumError).Err == strconv.ErrRange {
+			return "T_DNUMBER"
+		}
+	}
+	if name, ok := PHP_TOKEN_NAMES[kind]; ok {
+		return name
+	}
+	if len(text) == 1 {
+		// the punctuations are plain strings
+		return ""
+	}
+	panic("lexer: no PHP token name for " + kind.String())
+}
+
+// splitTrivia splits the leading trivia of a token into whitespace and comments.
+func splitTrivia(trivia []byte, pos int, end int) []PhpToken {
+	var tokens []PhpToken
+	for pos < end {
+		start := pos
+		name := "T_WHITESPACE"
+		switch {
+		case isDelimitedCommentStart(trivia, pos, end):
+			name = "T_COMMENT"
+			if pos+3 < end && trivia[pos+2] == '*' && isWhitespaceChar(trivia[pos+3]) {
+				name = "T_DOC_COMMENT"
+			}
+			pos += 2
+			scanDelimitedComment(trivia, &pos, end)
+		case trivia[pos] == '#' || isSingleLineCommentStart(trivia, pos, end):
+			name = "T_COMMENT"
+			scanSingleLineComment(trivia, &pos, end, LexStateScriptSectionParsed)
+			// PHP 7 keeps the new line as part of the comment
+			if pos < end && trivia[pos] == '\r' {
+				pos++
+			}
+			if pos < end && trivia[pos] == '\n' {
+				pos++
+			}
+		default:
+			for pos < end && isWhitespaceChar(trivia[pos]) {
+				pos++
+			}
+			if pos == start {
+				// not trivia as far as PHP is concerned
+				pos++
+				name = ""
+			}
+		}
+		tokens = append(tokens, PhpToken{Name: name, Text: string(trivia[start:pos])})
+	}
+	return tokens
+}
+
+func isWhitespaceChar(char byte) bool {
+	return char == ' ' || char == '\t' || char == '\n' || char == '\r'
+}
+
+func countLines(text string) int {
+	lines := 0
+	for i := 0; i < len(text); i++ {
+		if text[i] == '\n' || text[i] == '\r' && (i+1 == len(text) || text[i+1] != '\n') {
+			lines++
+		}
+	}
+	return lines
+}
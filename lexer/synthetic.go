@@ -0,0 +1,10 @@
+package lexer
+
+// NewSyntheticToken returns a token of the given kind and text that isn't
+// read from a source, for the trees built by hand. The token holds its
+// text, its Text ignores the source and it has no leading trivia. Its
+// offsets are offsets in its text, they must not be compared with the
+// offsets of the lexed tokens.
+func NewSyntheticToken(kind TokenKind, text string) *Token {
+	return &Token{Kind: kind, Length: len(text), Cat: TokenCatSynthetic, source: &text}
+}
@@ -6,6 +6,9 @@ const (
 	TokenCatNormal TokenCategory = iota
 	TokenCatSkipped
 	TokenCatMissing
+	// TokenCatSynthetic is the category of the tokens made by
+	// NewSyntheticToken, which aren't read from a source
+	TokenCatSynthetic
 )
 
 type Token struct {
@@ -14,12 +17,29 @@ type Token struct {
 	Start     int
 	Length    int
 	Cat       TokenCategory
+	// the text the offsets are offsets in, for a synthetic token or a copy
+	// made by WithSource, nil for the tokens read by the lexer
+	source *string
+}
+
+// WithSource returns a copy of the token whose texts are read from source,
+// whatever source is given to Text, FullText and LeadingTrivia. The copies
+// of the tokens of a file can share its source.
+func (r Token) WithSource(source *string) Token {
+	r.source = source
+	return r
+}
+
+// HasSource tells if the token holds the text it is read from, like a
+// synthetic token or a copy made by WithSource.
+func (r Token) HasSource() bool {
+	return r.source != nil
 }
 
 type TokenShortForm struct {
 	Kind       string `json:"kind"`
 	TextLength int    `json:"textLength"`
-	Text       string `json:text`
+	Text       string `json:"text"`
 }
 
 type TokenCompareForm struct {
@@ -33,21 +53,40 @@ type TokenFullForm struct {
 	Token
 	Kind       string `json:"kind"`
 	TextLength int    `json:"textLength"`
-	Text       string `json:text`
+	Text       string `json:"text"`
+}
+
+// Text returns the text of the token without its leading trivia.
+func (r Token) Text(source []byte) string {
+	if r.source != nil {
+		return (*r.source)[r.Start : r.FullStart+r.Length]
+	}
+	return string(source[r.Start : r.FullStart+r.Length])
+}
+
+// FullText returns the text of the token including its leading trivia.
+func (r Token) FullText(source []byte) string {
+	if r.source != nil {
+		return (*r.source)[r.FullStart : r.FullStart+r.Length]
+	}
+	return string(source[r.FullStart : r.FullStart+r.Length])
 }
 
-func (r Token) getText(text []byte) string {
-	s := r.Start
-	end := s + r.Length - (r.Start - r.FullStart)
-	return string(text[s:end])
+// LeadingTrivia returns the whitespace and comments before the token, a
+// synthetic token has none.
+func (r Token) LeadingTrivia(source []byte) string {
+	if r.source != nil {
+		return (*r.source)[r.FullStart:r.Start]
+	}
+	return string(source[r.FullStart:r.Start])
 }
 
-func (r Token) getShortForm(text []byte) TokenShortForm {
-	return TokenShortForm{r.Kind.String(), r.Length - (r.Start - r.FullStart), r.getText(text)}
+func (r Token) ShortForm(source []byte) TokenShortForm {
+	return TokenShortForm{r.Kind.String(), r.Length - (r.Start - r.FullStart), r.Text(source)}
 }
 
-func (r Token) getFullForm(text []byte) TokenFullForm {
-	t := TokenFullForm{Kind: r.Kind.String(), TextLength: r.Length - (r.Start - r.FullStart), Text: r.getText(text), Token: r}
+func (r Token) FullForm(source []byte) TokenFullForm {
+	t := TokenFullForm{Kind: r.Kind.String(), TextLength: r.Length - (r.Start - r.FullStart), Text: r.Text(source), Token: r}
 	return t
 }
 
@@ -0,0 +1,227 @@
+package lexer
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+var ErrNotNumericLiteral = errors.New("token is not a numeric literal")
+var ErrNotStringLiteral = errors.New("token is not a string literal")
+var ErrInvalidEscapeSequence = errors.New("invalid UTF-8 codepoint escape sequence")
+
+func isIntegerLiteralKind(kind TokenKind) bool {
+	switch kind {
+	case IntegerLiteralToken, OctalLiteralToken, HexadecimalLiteralToken, BinaryLiteralToken:
+		return true
+	}
+	return false
+}
+
+// IntegerValue returns the value of an integer literal in any base.
+// Like PHP, literals that do not fit in 64 bits are not integers: the
+// returned error is a *strconv.NumError wrapping strconv.ErrRange and
+// FloatValue should be used instead.
+func (r Token) IntegerValue(source []byte) (int64, error) {
+	if !isIntegerLiteralKind(r.Kind) {
+		return 0, ErrNotNumericLiteral
+	}
+	return strconv.ParseInt(integerLiteralText(r.Text(source)), 0, 64)
+}
+
+// FloatValue returns the value of a numeric literal as a float.
+func (r Token) FloatValue(source []byte) (float64, error) {
+	text := r.Text(source)
+	if r.Kind == FloatingLiteralToken {
+		return strconv.ParseFloat(text, 64)
+	}
+	if !isIntegerLiteralKind(r.Kind) {
+		return 0, ErrNotNumericLiteral
+	}
+	value, ok := new(big.Int).SetString(integerLiteralText(text), 0)
+	if !ok {
+		return 0, &strconv.NumError{Func: "FloatValue", Num: text, Err: strconv.ErrSyntax}
+	}
+	float, _ := new(big.Float).SetInt(value).Float64()
+	return float, nil
+}
+
+// integerLiteralText rewrites PHP octal literals (0777) in the 0o form
+// understood by strconv and math/big.
+func integerLiteralText(text string) string {
+	if len(text) > 1 && text[0] == '0' && text[1] >= '0' && text[1] <= '9' {
+		return "0o" + text[1:]
+	}
+	return text
+}
+
+// StringValue returns the contents of a string literal without its quotes
+// and with its escape sequences resolved. Single quoted, double quoted and
+// backtick literals without interpolation are supported, with or
+// without the binary b prefix.
+func (r Token) StringValue(source []byte) (string, error) {
+	text := r.Text(source)
+	if r.Kind != StringLiteralToken {
+		return "", ErrNotStringLiteral
+	}
+	if len(text) > 0 && (text[0] == 'b' || text[0] == 'B') {
+		text = text[1:]
+	}
+	if len(text) < 2 || text[len(text)-1] != text[0] {
+		return "", ErrNotStringLiteral
+	}
+	contents := text[1 : len(text)-1]
+	switch text[0] {
+	case '\'':
+		return UnescapeSingleQuoted(contents), nil
+	case '"', '`':
+		return unescape(contents, text[0])
+	}
+	return "", ErrNotStringLiteral
+}
+
+// NowdocValue returns the contents of the body of a nowdoc. Nowdocs have
+// no escape sequences, only the new line before the closing identifier
+// is not part of the value. The body of a heredoc, whose opener is not
+// quoted, is not a nowdoc.
+func (r Token) NowdocValue(source []byte) (string, error) {
+	if r.Kind != EncapsedAndWhitespace || !followsNowdocStart(source, r.FullStart) {
+		return "", ErrNotStringLiteral
+	}
+	text := r.Text(source)
+	if strings.HasSuffix(text, "\r\n") {
+		return text[:len(text)-2], nil
+	}
+	if strings.HasSuffix(text, "\n") || strings.HasSuffix(text, "\r") {
+		return text[:len(text)-1], nil
+	}
+	return text, nil
+}
+
+// followsNowdocStart reports if pos is right after a <<<'LABEL' line.
+func followsNowdocStart(source []byte, pos int) bool {
+	if pos <= 0 || pos > len(source) || source[pos-1] != '\n' {
+		return false
+	}
+	line := source[:pos-1]
+	if start := bytes.LastIndexByte(line, '\n'); start != -1 {
+		line = line[start+1:]
+	}
+	opener := bytes.LastIndex(line, []byte("<<<"))
+	if opener == -1 {
+		return false
+	}
+	label := bytes.TrimRight(bytes.TrimLeft(line[opener+3:], " \t"), "\r")
+	return len(label) > 2 && label[0] == '\'' && label[len(label)-1] == '\''
+}
+
+// UnescapeSingleQuoted resolves the \' and \\ escape sequences of a single
+// quoted string.
+func UnescapeSingleQuoted(contents string) string {
+	if strings.IndexByte(contents, '\\') == -1 {
+		return contents
+	}
+	var b strings.Builder
+	for i := 0; i < len(contents); i++ {
+		if contents[i] == '\\' && i+1 < len(contents) && (contents[i+1] == '\'' || contents[i+1] == '\\') {
+			i++
+		}
+		b.WriteByte(contents[i])
+	}
+	return b.String()
+}
+
+// UnescapeDoubleQuoted resolves the escape sequences of a double quoted
+// string or of a piece of it between interpolations. Unknown escape
+// sequences are kept as they are, as PHP does.
+func UnescapeDoubleQuoted(contents string) (string, error) {
+	return unescape(contents, '"')
+}
+
+// UnescapeHeredoc is like UnescapeDoubleQuoted but \" is not an escape
+// sequence in heredoc bodies.
+func UnescapeHeredoc(contents string) (string, error) {
+	return unescape(contents, 0)
+}
+
+func unescape(contents string, quote byte) (string, error) {
+	if strings.IndexByte(contents, '\\') == -1 {
+		return contents, nil
+	}
+	var b strings.Builder
+	for i := 0; i < len(contents); i++ {
+		char := contents[i]
+		if char != '\\' || i+1 == len(contents) {
+			b.WriteByte(char)
+			continue
+		}
+		i++
+		switch next := contents[i]; next {
+		case 'n':
+			b.WriteByte('\n')
+		case 't':
+			b.WriteByte('\t')
+		case 'r':
+			b.WriteByte('\r')
+		case 'v':
+			b.WriteByte('\v')
+		case 'e':
+			b.WriteByte(0x1b)
+		case 'f':
+			b.WriteByte('\f')
+		case '\\', '$':
+			b.WriteByte(next)
+		case 'x', 'X':
+			end := i + 1
+			for end < len(contents) && end < i+3 && isHexadecimalDigit(rune(contents[end])) {
+				end++
+			}
+			if end == i+1 {
+				b.WriteByte('\\')
+				b.WriteByte(next)
+				continue
+			}
+			value, _ := strconv.ParseUint(contents[i+1:end], 16, 8)
+			b.WriteByte(byte(value))
+			i = end - 1
+		case 'u':
+			if i+1 == len(contents) || contents[i+1] != '{' {
+				b.WriteByte('\\')
+				b.WriteByte(next)
+				continue
+			}
+			end := strings.IndexByte(contents[i:], '}')
+			if end == -1 {
+				return "", ErrInvalidEscapeSequence
+			}
+			end += i
+			codepoint, err := strconv.ParseUint(contents[i+2:end], 16, 32)
+			if err != nil || codepoint > utf8.MaxRune {
+				return "", ErrInvalidEscapeSequence
+			}
+			b.WriteRune(rune(codepoint))
+			i = end
+		default:
+			if next == quote {
+				b.WriteByte(next)
+				continue
+			}
+			if isOctalDigitChar(rune(next)) {
+				end := i
+				for end < len(contents) && end < i+3 && isOctalDigitChar(rune(contents[end])) {
+					end++
+				}
+				value, _ := strconv.ParseUint(contents[i:end], 8, 16)
+				b.WriteByte(byte(value))
+				i = end - 1
+				continue
+			}
+			b.WriteByte('\\')
+			b.WriteByte(next)
+		}
+	}
+	return b.String(), nil
+}
@@ -201,3 +201,18 @@ const (
 	TemplateStringStart
 	TemplateStringEnd
 )
+
+// tokenKindsByName maps the names of TokenKind.String back to the kinds.
+var tokenKindsByName = func() map[string]TokenKind {
+	m := make(map[string]TokenKind, len(_TokenKind_index)-1)
+	for kind := TokenKind(0); int(kind) < len(_TokenKind_index)-1; kind++ {
+		m[kind.String()] = kind
+	}
+	return m
+}()
+
+// TokenKindByName returns the kind of a name returned by String.
+func TokenKindByName(name string) (TokenKind, bool) {
+	kind, ok := tokenKindsByName[name]
+	return kind, ok
+}
@@ -1,5 +1,9 @@
 package lexer
 
+import (
+	"sort"
+)
+
 var OPERATORS_AND_PUNCTUATORS = map[string]TokenKind{
 	"[":         OpenBracketToken,
 	"]":         CloseBracketToken,
@@ -196,28 +200,97 @@ var KEYWORDS = map[string]TokenKind{
 	"yield from":   YieldFromKeyword,
 }
 
-func valueInMap(v TokenKind, m map[string]TokenKind) bool {
-	for _, value := range m {
-		if v == value {
-			return true
-		}
+const tokenKindCount = int(TemplateStringEnd) + 1
+
+// TokenKindSet is a set of token kinds stored as a bitset, membership
+// tests are a shift and a mask.
+type TokenKindSet [(tokenKindCount + 63) / 64]uint64
+
+func NewTokenKindSet(kinds ...TokenKind) TokenKindSet {
+	var set TokenKindSet
+	for _, kind := range kinds {
+		set[kind/64] |= 1 << (uint(kind) % 64)
 	}
-	return false
+	return set
 }
 
-func GetReservedWords() []TokenKind {
+func (s *TokenKindSet) Contains(kind TokenKind) bool {
+	if kind < 0 || int(kind) >= tokenKindCount {
+		return false
+	}
+	return s[kind/64]&(1<<(uint(kind)%64)) != 0
+}
+
+// sortedKinds returns the kinds of a table ordered by kind so the order
+// does not depend on map iteration.
+func sortedKinds(m map[string]TokenKind) []TokenKind {
+	kinds := make([]TokenKind, 0, len(m))
+	for _, kind := range m {
+		kinds = append(kinds, kind)
+	}
+	sort.Slice(kinds, func(i, j int) bool { return kinds[i] < kinds[j] })
+	return kinds
+}
+
+func concatKinds(lists ...[]TokenKind) []TokenKind {
 	var kinds []TokenKind
-	for _, v := range RESERVED_WORDS {
-		kinds = append(kinds, v)
+	for _, list := range lists {
+		kinds = append(kinds, list...)
 	}
 	return kinds
 }
 
+var reservedWordKinds = sortedKinds(RESERVED_WORDS)
+var keywordKinds = sortedKinds(KEYWORDS)
+
+// like in mstpp, Name comes first: it is the kind of the missing token
+// created when none of the kinds is found.
+var nameOrKeywordOrReservedWordKinds = concatKinds([]TokenKind{Name}, keywordKinds, reservedWordKinds)
+var nameOrReservedWordKinds = concatKinds([]TokenKind{Name}, reservedWordKinds)
+
+var reservedWordSet = NewTokenKindSet(reservedWordKinds...)
+var keywordSet = NewTokenKindSet(keywordKinds...)
+var nameOrKeywordOrReservedWordSet = NewTokenKindSet(nameOrKeywordOrReservedWordKinds...)
+
+// keywordsAndReservedWords merges KEYWORDS and RESERVED_WORDS for the
+// lexer, which looks up every name it scans.
+var keywordsAndReservedWords, maxKeywordLength = func() (map[string]TokenKind, int) {
+	m := make(map[string]TokenKind, len(KEYWORDS)+len(RESERVED_WORDS))
+	maxLength := 0
+	for _, table := range []map[string]TokenKind{KEYWORDS, RESERVED_WORDS} {
+		for text, kind := range table {
+			m[text] = kind
+			if len(text) > maxLength {
+				maxLength = len(text)
+			}
+		}
+	}
+	return m, maxLength
+}()
+
+// maxOperatorLength is the length of the longest entry of
+// OPERATORS_AND_PUNCTUATORS for each first byte.
+var maxOperatorLength = func() [256]int {
+	var lengths [256]int
+	for text := range OPERATORS_AND_PUNCTUATORS {
+		if len(text) > lengths[text[0]] {
+			lengths[text[0]] = len(text)
+		}
+	}
+	return lengths
+}()
+
+const maxOperatorsLength = 7
+
+func GetReservedWords() []TokenKind {
+	return concatKinds(reservedWordKinds)
+}
+
 func IsReservedWordToken(v TokenKind) bool {
-	return valueInMap(v, RESERVED_WORDS)
+	return reservedWordSet.Contains(v)
 }
 func IsKeywordToken(v TokenKind) bool {
-	return valueInMap(v, KEYWORDS)
+	return keywordSet.Contains(v)
 }
 
 func IsKeywordOrReserverdWordToken(v TokenKind) bool {
@@ -225,31 +298,13 @@ func IsKeywordOrReserverdWordToken(v TokenKind) bool {
 }
 
 func IsNameOrKeywordOrReservedWordTokens(v TokenKind) bool {
-	for _, value := range GetNameOrKeywordOrReservedWordTokens() {
-		if v == value {
-			return true
-		}
-	}
-	return false
+	return nameOrKeywordOrReservedWordSet.Contains(v)
 }
 
 func GetNameOrKeywordOrReservedWordTokens() []TokenKind {
-	var tokens []TokenKind
-	for _, value := range RESERVED_WORDS {
-		tokens = append(tokens, value)
-	}
-	for _, value := range KEYWORDS {
-		tokens = append(tokens, value)
-	}
-	tokens = append(tokens, Name)
-	return tokens
+	return concatKinds(nameOrKeywordOrReservedWordKinds)
 }
 
 func GetNameOrReservedWordTokens() []TokenKind {
-	var tokens []TokenKind
-	for _, value := range RESERVED_WORDS {
-		tokens = append(tokens, value)
-	}
-	tokens = append(tokens, Name)
-	return tokens
+	return concatKinds(nameOrReservedWordKinds)
 }
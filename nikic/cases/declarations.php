@@ -0,0 +1,32 @@
+a<?php
+namespace A\B;
+use X\Y as Z, W; use function f\g;
+use X\{A, B as C};
+abstract class C extends D implements E, \F {
+    use T1, T2 { a as protected b; T1::c insteadof T2; }
+    const X = 1, Y = 2;
+    public static $p = 1, $q;
+    final protected function &m(?int $a = 1, B ...$rest): ?self { return; }
+    abstract function n();
+}
+interface I extends J { function x(); }
+trait T { var $x; }
+$f = static function &($a) use ($b, &$c): int { yield $k => $v; yield from $g; };
+list($a, list(, $b), 'k' => $c) = [1, 'a' => &$d];
+foreach ($a as $k => &$v): endforeach;
+switch ($x) { case 1: break 2; default: continue; }
+try {} catch (A $e) {} finally {}
+declare(strict_types=1);
+static $s = 1; global $g;
+const K = 1;
+(int) $x; @f(); -$a; !$b; ~$c; ++$i; $i--;
+isset($a, $b); empty($c); exit(1); die; print $x; eval('1');
+include 'a.php'; require_once 'b';
+new A(1); new class(2) extends B {}; A::b(); A::$c; A::D; $a->b(); $a::$b;
+$a instanceof B; $a ? $b : $c; $a ?: $c; $a ?? $b; $a .= $b; $a =& $b;
+echo <<<'N'
+raw
+N;
+label: goto label;
+?>
+tail<?= $x ?>
@@ -0,0 +1,15 @@
+<?php
+"abc\n\x41\101\u{1F600}\q\$\e";
+'x\'y\\z\n';
+"$f[abc] $f[1] $a->b {$a['x']} ${e} {$a->b()}\n";
+$a->{'b'}; $a->$b; $a::{'c'}();
+1.5; 0x1F; 0b11; 017; 1e3; 9223372036854775807; 9223372036854775808; 0xFFFFFFFFFFFFFFFF;
+`a $b `; b'x'; b"y$z"; "\"$z\"";
+<<<EOT
+x $y z
+  {$a[1]}
+EOT;
+<<<'N'
+raw $x
+N;
+__LINE__; __CLASS__; true; null; \Foo\bar(1); namespace\baz(); $$v; ${'x'};
@@ -0,0 +1,18 @@
+<?php
+/** Doc */
+// line
+function f(array $a, callable $b, \int $c, ?string ...$d): void {}
+if ($a): elseif ($b): else: endif;
+if ($a) echo 1; else if ($b) { echo 2; }
+while (1) { break; }
+do $a++; while ($a < 3);
+for ($i = 0, $j = 1; $i < 3; $i++) {}
+for (;;);
+namespace B { const X = A::class; }
+namespace { $a = array(1, 2,); [$a, [$b]] = $c; foreach ($a as [$b, $c]) {} }
+$x = "\x41\101\u{1F600}\q\$\e";
+$y = 0x7FFFFFFFFFFFFFFF + 0xFFFFFFFFFFFFFFFF + 1.0 + 1e100 + 017 + 0b101;
+static::$a; new static; parent::f(); $a->{'b'}(); $a->$b; $$a->c;
+unset($a[1], $b);
+throw new E;
+__halt_compiler();
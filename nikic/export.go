@@ -0,0 +1,713 @@
+// Package nikic exports the trees of the parser as the abstract syntax
+// trees of nikic/PHP-Parser 4, the Stmt_Class and Expr_MethodCall nodes
+// its json serialization is made of, for the php tools that read them.
+//
+// The concrete tree keeps every token, the exported tree only what
+// PHP-Parser keeps: parentheses, semicolons and blocks are dropped, the
+// strings and numbers are evaluated and the statements after a
+// `namespace Name;` are moved into it. Names aren't resolved. Missing
+// expressions become Expr_Error nodes and skipped tokens are dropped, as
+// the error recovery of PHP-Parser does.
+package nikic
+
+import (
+	"sort"
+
+	"github.com/emilioastarita/gphp/ast"
+	"github.com/emilioastarita/gphp/lexer"
+)
+
+// Export converts a tree to the statements of PHP-Parser.
+func Export(sourceFile *ast.SourceFileNode) []*Node {
+	e := &exporter{source: sourceFile.FileContents, spans: map[ast.Node]span{}}
+	for i, c := range e.source {
+		if c == '\n' {
+			e.newlines = append(e.newlines, i)
+		}
+	}
+	return e.namespaces(e.statements(sourceFile.StatementList))
+}
+
+type exporter struct {
+	source []byte
+	// newlines are the offsets of the line feeds of the source
+	newlines []int
+	spans    map[ast.Node]span
+}
+
+// span is the source of a node, from the start of its first token to the
+// end of its last one. fullStart is the start of the trivia of the first
+// token, where the comments are. A span without tokens has start -1.
+type span struct {
+	fullStart, start, end int
+}
+
+var noSpan = span{-1, -1, -1}
+
+func (s span) merge(other span) span {
+	if s.start < 0 {
+		return other
+	}
+	if other.start < 0 {
+		return s
+	}
+	if other.start < s.start {
+		s.fullStart, s.start = other.fullStart, other.start
+	}
+	if other.end > s.end {
+		s.end = other.end
+	}
+	return s
+}
+
+func tokenSpan(token *lexer.Token) span {
+	if token == nil || token.Cat == lexer.TokenCatMissing {
+		return noSpan
+	}
+	return span{token.FullStart, token.Start, token.FullStart + token.Length}
+}
+
+// span returns the span of a child, a token, a node or a list of them.
+func (e *exporter) span(child interface{}) span {
+	switch child := child.(type) {
+	case *lexer.Token:
+		return tokenSpan(child)
+	case []*lexer.Token:
+		s := noSpan
+		for _, token := range child {
+			s = s.merge(tokenSpan(token))
+		}
+		return s
+	case []ast.Node:
+		s := noSpan
+		for _, node := range child {
+			s = s.merge(e.span(node))
+		}
+		return s
+	case ast.Node:
+		if s, ok := e.spans[child]; ok {
+			return s
+		}
+		s := noSpan
+		for _, c := range child.Children() {
+			s = s.merge(e.span(c))
+		}
+		e.spans[child] = s
+		return s
+	}
+	return noSpan
+}
+
+func (e *exporter) line(pos int) int {
+	return sort.SearchInts(e.newlines, pos) + 1
+}
+
+func (e *exporter) attributes(s span) Attributes {
+	if s.start < 0 {
+		return Attributes{StartLine: -1, EndLine: -1, StartFilePos: -1, EndFilePos: -1}
+	}
+	return Attributes{
+		StartLine:    e.line(s.start),
+		EndLine:      e.line(s.end - 1),
+		StartFilePos: s.start,
+		EndFilePos:   s.end - 1,
+		Comments:     e.comments(s.fullStart, s.start),
+	}
+}
+
+// comments returns the comments in the trivia between fullStart and
+// start.
+func (e *exporter) comments(fullStart, start int) []Comment {
+	var comments []Comment
+	trivia := e.source[:start]
+	for i := fullStart; i < start; {
+		c := trivia[i]
+		if c == ' ' || c == '\t' || c == '\n' || c == '\r' {
+			i++
+			continue
+		}
+		end := i
+		switch {
+		case c == '#' || c == '/' && i+1 < start && trivia[i+1] == '/':
+			for end < start && trivia[end] != '\n' && trivia[end] != '\r' {
+				end++
+			}
+		case c == '/' && i+1 < start && trivia[i+1] == '*':
+			end = i + 2
+			for end < start && !(trivia[end-1] == '*' && trivia[end] == '/' && end > i+2) {
+				end++
+			}
+			if end < start {
+				end++
+			}
+		default:
+			return comments
+		}
+		text := string(trivia[i:end])
+		comments = append(comments, Comment{
+			Doc:        len(text) > 4 && text[:3] == "/**",
+			Text:       text,
+			Line:       e.line(i),
+			FilePos:    i,
+			EndLine:    e.line(end - 1),
+			EndFilePos: end - 1,
+		})
+		i = end
+	}
+	return comments
+}
+
+// node creates a node of nodeType from the names and values of its
+// subnodes.
+func (e *exporter) node(nodeType string, s span, namesAndValues ...interface{}) *Node {
+	n := &Node{Type: nodeType, Attributes: e.attributes(s)}
+	n.SubNodes = make([]SubNode, 0, len(namesAndValues)/2)
+	for i := 0; i < len(namesAndValues); i += 2 {
+		value := namesAndValues[i+1]
+		// a null, not a nil *Node or []*Node
+		if node, ok := value.(*Node); ok && node == nil {
+			value = nil
+		}
+		if nodes, ok := value.([]*Node); ok && nodes == nil {
+			value = nil
+		}
+		n.SubNodes = append(n.SubNodes, SubNode{namesAndValues[i].(string), value})
+	}
+	return n
+}
+
+func (e *exporter) text(token *lexer.Token) string {
+	if token == nil || token.Cat == lexer.TokenCatMissing {
+		return ""
+	}
+	return token.Text(e.source)
+}
+
+// statements converts a list of statements, the blocks in it are
+// flattened.
+func (e *exporter) statements(nodes []ast.Node) []*Node {
+	statements := []*Node{}
+	for _, node := range nodes {
+		statements = append(statements, e.statement(node)...)
+	}
+	return statements
+}
+
+// statementsOf converts the statements of a loop, a condition or a
+// declare: a block, the list of the alternative syntax or one statement.
+func (e *exporter) statementsOf(statements ast.NodeOrNodeColl) []*Node {
+	switch statements := statements.(type) {
+	case []ast.Node:
+		return e.statements(statements)
+	case ast.Node:
+		return e.statement(statements)
+	}
+	return []*Node{}
+}
+
+func (e *exporter) statement(node ast.Node) []*Node {
+	s := e.span(node)
+	switch n := node.(type) {
+	case *ast.CompoundStatementNode:
+		return e.statements(n.Statements)
+	case *ast.InlineHtml:
+		var statements []*Node
+		if n.Text != nil {
+			statements = append(statements, e.node("Stmt_InlineHTML", tokenSpan(n.Text), "value", e.text(n.Text)))
+		}
+		if n.EchoStatement != nil {
+			echo := n.EchoStatement.Expression[0].(*ast.EchoExpression)
+			s := tokenSpan(n.ScriptSectionStartTag).merge(e.span(n.EchoStatement))
+			statements = append(statements, e.node("Stmt_Echo", s, "exprs", e.expressions(echo.Expressions)))
+		}
+		return statements
+	case *ast.ExpressionStatement:
+		if len(n.Expression) == 0 {
+			return nil
+		}
+		switch expression := n.Expression[0].(type) {
+		case *ast.EchoExpression:
+			return []*Node{e.node("Stmt_Echo", s, "exprs", e.expressions(expression.Expressions))}
+		case *ast.UnsetIntrinsicExpression:
+			return []*Node{e.node("Stmt_Unset", s, "vars", e.expressions(expression.Expressions))}
+		}
+		return []*Node{e.node("Stmt_Expression", s, "expr", e.expression(n.Expression[0]))}
+	case *ast.ReturnStatement:
+		return []*Node{e.node("Stmt_Return", s, "expr", e.expression(n.Expression))}
+	case *ast.ThrowStatement:
+		return []*Node{e.node("Stmt_Throw", s, "expr", e.expression(n.Expression))}
+	case *ast.IfStatementNode:
+		elseIfs := []*Node{}
+		for _, clause := range n.ElseIfClauses {
+			if clause, ok := clause.(*ast.ElseIfClauseNode); ok {
+				elseIfs = append(elseIfs, e.node("Stmt_ElseIf", e.span(clause),
+					"cond", e.expression(clause.Expression),
+					"stmts", e.statementsOf(clause.Statements)))
+			}
+		}
+		var elseNode *Node
+		if clause, ok := n.ElseClause.(*ast.ElseClauseNode); ok {
+			elseNode = e.node("Stmt_Else", e.span(clause), "stmts", e.statementsOf(clause.Statements))
+		}
+		return []*Node{e.node("Stmt_If", s,
+			"cond", e.expression(n.Expression),
+			"stmts", e.statementsOf(n.Statements),
+			"elseifs", elseIfs,
+			"else", elseNode)}
+	case *ast.WhileStatement:
+		return []*Node{e.node("Stmt_While", s,
+			"cond", e.expression(n.Expression),
+			"stmts", e.statementsOf(n.Statements))}
+	case *ast.DoStatement:
+		return []*Node{e.node("Stmt_Do", s,
+			"stmts", e.statementsOf(n.Statement),
+			"cond", e.expression(n.Expression))}
+	case *ast.ForStatement:
+		return []*Node{e.node("Stmt_For", s,
+			"init", e.expressions(n.ForInitializer),
+			"cond", e.expressions(n.ForControl),
+			"loop", e.expressions(n.ForEndOfLoop),
+			"stmts", e.statementsOf(n.Statements))}
+	case *ast.ForeachStatement:
+		var key, value ast.Node
+		byRef := false
+		if foreachKey, ok := n.ForeachKey.(*ast.ForeachKey); ok {
+			key = foreachKey.Expression
+		}
+		if foreachValue, ok := n.ForeachValue.(*ast.ForeachValue); ok {
+			value = foreachValue.Expression
+			byRef = foreachValue.Ampersand != nil
+		}
+		return []*Node{e.node("Stmt_Foreach", s,
+			"expr", e.expression(n.ForEachCollectionName),
+			"keyVar", e.expression(key),
+			"byRef", byRef,
+			"valueVar", e.assignable(value),
+			"stmts", e.statementsOf(n.Statements))}
+	case *ast.SwitchStatementNode:
+		cases := []*Node{}
+		for _, c := range n.CaseStatements {
+			if c, ok := c.(*ast.CaseStatementNode); ok {
+				cases = append(cases, e.node("Stmt_Case", e.span(c),
+					"cond", e.expression(c.Expression),
+					"stmts", e.statements(c.StatementList)))
+			}
+		}
+		return []*Node{e.node("Stmt_Switch", s, "cond", e.expression(n.Expression), "cases", cases)}
+	case *ast.BreakOrContinueStatement:
+		nodeType := "Stmt_Break"
+		if n.BreakOrContinueKeyword.Kind == lexer.ContinueKeyword {
+			nodeType = "Stmt_Continue"
+		}
+		return []*Node{e.node(nodeType, s, "num", e.expression(n.BreakoutLevel))}
+	case *ast.TryStatement:
+		catches := []*Node{}
+		for _, c := range n.CatchClauses {
+			if c, ok := c.(*ast.CatchClause); ok {
+				catches = append(catches, e.node("Stmt_Catch", e.span(c),
+					"types", e.names(c.QualifiedName),
+					"var", e.variable(c.VariableName),
+					"stmts", e.statementsOf(c.CompoundStatement)))
+			}
+		}
+		var finally *Node
+		if clause, ok := n.FinallyClause.(*ast.FinallyClause); ok {
+			finally = e.node("Stmt_Finally", e.span(clause), "stmts", e.statementsOf(clause.CompoundStatement))
+		}
+		return []*Node{e.node("Stmt_TryCatch", s,
+			"stmts", e.statementsOf(n.CompoundStatement),
+			"catches", catches,
+			"finally", finally)}
+	case *ast.GotoStatement:
+		return []*Node{e.node("Stmt_Goto", s, "name", e.identifier(n.Name))}
+	case *ast.NamedLabelStatement:
+		label := e.node("Stmt_Label", tokenSpan(n.Name).merge(tokenSpan(n.Colon)), "name", e.identifier(n.Name))
+		return append([]*Node{label}, e.statementsOf(n.Statement)...)
+	case *ast.GlobalDeclaration:
+		return []*Node{e.node("Stmt_Global", s, "vars", e.expressions(n.VariableNameList))}
+	case *ast.FunctionStaticDeclaration:
+		vars := []*Node{}
+		for _, element := range elements(n.StaticVariableNameList) {
+			if declaration, ok := element.(*ast.StaticVariableDeclaration); ok {
+				vars = append(vars, e.node("Stmt_StaticVar", e.span(declaration),
+					"var", e.variable(declaration.VariableName),
+					"default", e.expression(declaration.Assignment)))
+			}
+		}
+		return []*Node{e.node("Stmt_Static", s, "vars", vars)}
+	case *ast.ConstDeclaration:
+		return []*Node{e.node("Stmt_Const", s, "consts", e.consts(n.ConstElements))}
+	case *ast.DeclareStatement:
+		declares := []*Node{}
+		if directive, ok := n.DeclareDirective.(*ast.DeclareDirective); ok {
+			declares = append(declares, e.node("Stmt_DeclareDeclare", e.span(directive),
+				"key", e.identifier(directive.Name),
+				"value", e.literal(directive.Literal)))
+		}
+		var statements []*Node
+		if n.Statements != nil {
+			statements = e.statementsOf(n.Statements)
+		}
+		return []*Node{e.node("Stmt_Declare", s, "declares", declares, "stmts", statements)}
+	case *ast.NamespaceDefinition:
+		var statements []*Node
+		kind := namespaceSemicolon
+		if block, ok := n.CompoundStatementOrSemicolon.(*ast.CompoundStatementNode); ok {
+			statements = e.statements(block.Statements)
+			kind = namespaceBraced
+		}
+		namespace := e.node("Stmt_Namespace", s, "name", e.name(n.Name), "stmts", statements)
+		namespace.Attributes.Kind = kind
+		return []*Node{namespace}
+	case *ast.NamespaceUseDeclaration:
+		return e.use(n, s)
+	case *ast.FunctionDeclaration:
+		return []*Node{e.node("Stmt_Function", s,
+			"attrGroups", []*Node{},
+			"byRef", n.ByRefToken != nil,
+			"name", e.identifier(nameToken(n.Name)),
+			"params", e.params(n.Parameters),
+			"returnType", e.typeNode(n.ReturnType, n.FunctionReturnType.QuestionToken),
+			"stmts", e.statementsOf(n.CompoundStatementOrSemicolon))}
+	case *ast.ClassDeclaration:
+		flags := 0
+		if n.AbstractOrFinalModifier != nil {
+			flags = modifierFlags[n.AbstractOrFinalModifier.Kind]
+		}
+		return []*Node{e.classDeclaration(s, flags, e.identifier(n.Name), n.ClassBaseClause, n.ClassInterfaceClause, n.ClassMembers)}
+	case *ast.InterfaceDeclaration:
+		var extends []*Node
+		if clause, ok := n.InterfaceBaseClause.(*ast.InterfaceBaseClause); ok {
+			extends = e.names(clause.InterfaceNameList)
+		} else {
+			extends = []*Node{}
+		}
+		var members []ast.Node
+		if interfaceMembers, ok := n.InterfaceMembers.(*ast.InterfaceMembers); ok {
+			members = interfaceMembers.InterfaceMemberDeclarations
+		}
+		return []*Node{e.node("Stmt_Interface", s,
+			"attrGroups", []*Node{},
+			"name", e.identifier(n.Name),
+			"extends", extends,
+			"stmts", e.members(members))}
+	case *ast.TraitDeclaration:
+		var members []ast.Node
+		if traitMembers, ok := n.TraitMembers.(*ast.TraitMembers); ok {
+			members = traitMembers.TraitMemberDeclarations
+		}
+		return []*Node{e.node("Stmt_Trait", s,
+			"attrGroups", []*Node{},
+			"name", e.identifier(n.Name),
+			"stmts", e.members(members))}
+	}
+	// empty statements, skipped tokens and the members of classes out of
+	// their class
+	return nil
+}
+
+// The kinds of Stmt_Namespace.
+const (
+	namespaceSemicolon = 1
+	namespaceBraced    = 2
+)
+
+// namespaces moves the statements after a `namespace Name;` into it, up to
+// the next namespace.
+func (e *exporter) namespaces(statements []*Node) []*Node {
+	var result []*Node
+	var current *Node
+	for _, statement := range statements {
+		if statement.Type == "Stmt_Namespace" {
+			current = nil
+			if statement.Attributes.Kind == namespaceSemicolon {
+				current = statement
+				current.SubNodes[1].Value = []*Node{}
+			}
+			result = append(result, statement)
+			continue
+		}
+		if current == nil {
+			result = append(result, statement)
+			continue
+		}
+		current.SubNodes[1].Value = append(current.SubNodes[1].Value.([]*Node), statement)
+		current.Attributes.EndLine = statement.Attributes.EndLine
+		current.Attributes.EndFilePos = statement.Attributes.EndFilePos
+	}
+	if result == nil {
+		result = []*Node{}
+	}
+	return result
+}
+
+// The use types of Stmt_Use, Stmt_GroupUse and Stmt_UseUse.
+const (
+	useUnknown  = 0
+	useNormal   = 1
+	useFunction = 2
+	useConstant = 3
+)
+
+func useType(token *lexer.Token) int {
+	if token == nil {
+		return useNormal
+	}
+	switch token.Kind {
+	case lexer.FunctionKeyword:
+		return useFunction
+	case lexer.ConstKeyword:
+		return useConstant
+	}
+	return useNormal
+}
+
+// use converts a use declaration to a Stmt_Use, or to a Stmt_GroupUse for
+// the `use A\{B, C}` ones.
+func (e *exporter) use(n *ast.NamespaceUseDeclaration, s span) []*Node {
+	uses := []*Node{}
+	for _, element := range elements(n.UseClauses) {
+		clause, ok := element.(*ast.NamespaceUseClause)
+		if !ok {
+			continue
+		}
+		if clause.GroupClauses == nil {
+			uses = append(uses, e.useUse(e.span(clause), useUnknown, clause.NamespaceName, clause.NamespaceAliasingClause))
+			continue
+		}
+		// the type of the declaration applies to the group, without it
+		// every clause of the group has its own
+		groupType := useUnknown
+		if n.FunctionOrConst != nil {
+			groupType = useType(n.FunctionOrConst)
+		}
+		groupUses := []*Node{}
+		for _, element := range elements(clause.GroupClauses) {
+			groupClause, ok := element.(*ast.NamespaceUseGroupClause)
+			if !ok {
+				continue
+			}
+			t := useUnknown
+			if groupType == useUnknown {
+				t = useType(groupClause.FunctionOrConst)
+			}
+			groupUses = append(groupUses, e.useUse(e.span(groupClause), t, groupClause.NamespaceName, groupClause.NamespaceAliasingClause))
+		}
+		return []*Node{e.node("Stmt_GroupUse", s,
+			"type", groupType,
+			"prefix", e.name(clause.NamespaceName),
+			"uses", groupUses)}
+	}
+	return []*Node{e.node("Stmt_Use", s, "type", useType(n.FunctionOrConst), "uses", uses)}
+}
+
+func (e *exporter) useUse(s span, t int, name ast.Node, aliasingClause ast.Node) *Node {
+	var alias *Node
+	if clause, ok := aliasingClause.(*ast.NamespaceAliasingClause); ok {
+		alias = e.identifier(clause.Name)
+	}
+	useName := e.name(name)
+	if useName != nil {
+		// `use \A` is `use A`
+		useName.Type = "Name"
+	}
+	return e.node("Stmt_UseUse", s, "type", t, "name", useName, "alias", alias)
+}
+
+// The flags of the modifiers of the members and of the classes.
+var modifierFlags = map[lexer.TokenKind]int{
+	lexer.PublicKeyword:    1,
+	lexer.ProtectedKeyword: 2,
+	lexer.PrivateKeyword:   4,
+	lexer.StaticKeyword:    8,
+	lexer.AbstractKeyword:  16,
+	lexer.FinalKeyword:     32,
+}
+
+func flags(modifiers []*lexer.Token) int {
+	flags := 0
+	for _, modifier := range modifiers {
+		flags |= modifierFlags[modifier.Kind]
+	}
+	return flags
+}
+
+// classDeclaration converts a class declaration or the class of an
+// anonymous class creation, name is nil for the anonymous ones.
+func (e *exporter) classDeclaration(s span, flags int, name *Node, baseClause, interfaceClause, classMembers ast.Node) *Node {
+	var extends *Node
+	if clause, ok := baseClause.(*ast.ClassBaseClause); ok {
+		extends = e.name(clause.BaseClass)
+	}
+	implements := []*Node{}
+	if clause, ok := interfaceClause.(*ast.ClassInterfaceClause); ok {
+		implements = e.names(clause.InterfaceNameList)
+	}
+	var members []ast.Node
+	if classMembersNode, ok := classMembers.(*ast.ClassMembersNode); ok {
+		members = classMembersNode.ClassMemberDeclarations
+	}
+	return e.node("Stmt_Class", s,
+		"attrGroups", []*Node{},
+		"flags", flags,
+		"name", name,
+		"extends", extends,
+		"implements", implements,
+		"stmts", e.members(members))
+}
+
+// members converts the members of a class, an interface or a trait.
+func (e *exporter) members(members []ast.Node) []*Node {
+	statements := []*Node{}
+	for _, member := range members {
+		s := e.span(member)
+		switch n := member.(type) {
+		case *ast.MethodDeclaration:
+			var body []*Node
+			if _, ok := n.CompoundStatementOrSemicolon.(*ast.CompoundStatementNode); ok {
+				body = e.statementsOf(n.CompoundStatementOrSemicolon)
+			}
+			statements = append(statements, e.node("Stmt_ClassMethod", s,
+				"attrGroups", []*Node{},
+				"flags", flags(n.Modifiers),
+				"byRef", n.ByRefToken != nil,
+				"name", e.identifier(nameToken(n.Name)),
+				"params", e.params(n.Parameters),
+				"returnType", e.typeNode(n.ReturnType, n.FunctionReturnType.QuestionToken),
+				"stmts", body))
+		case *ast.PropertyDeclaration:
+			props := []*Node{}
+			for _, element := range elements(n.PropertyElements) {
+				var variable, value ast.Node = element, nil
+				if assignment, ok := element.(*ast.AssignmentExpression); ok {
+					variable, value = assignment.LeftOperand, assignment.RightOperand
+				}
+				var name *Node
+				if v, ok := variable.(*ast.Variable); ok {
+					name = e.varLikeIdentifier(v)
+				}
+				props = append(props, e.node("Stmt_PropertyProperty", e.span(element),
+					"name", name,
+					"default", e.expression(value)))
+			}
+			statements = append(statements, e.node("Stmt_Property", s,
+				"attrGroups", []*Node{},
+				"flags", flags(n.Modifiers),
+				"type", nil,
+				"props", props))
+		case *ast.ClassConstDeclaration:
+			statements = append(statements, e.node("Stmt_ClassConst", s,
+				"attrGroups", []*Node{},
+				"flags", flags(n.Modifiers),
+				"consts", e.consts(n.ConstElements)))
+		case *ast.TraitUseClause:
+			adaptations := []*Node{}
+			for _, element := range elements(n.TraitSelectAndAliasClauses) {
+				if clause, ok := element.(*ast.TraitSelectOrAliasClause); ok {
+					adaptations = append(adaptations, e.adaptation(clause))
+				}
+			}
+			statements = append(statements, e.node("Stmt_TraitUse", s,
+				"traits", e.names(n.TraitNameList),
+				"adaptations", adaptations))
+		}
+	}
+	return statements
+}
+
+// adaptation converts the `as` and `insteadof` clauses of a trait use.
+func (e *exporter) adaptation(clause *ast.TraitSelectOrAliasClause) *Node {
+	var trait, method *Node
+	switch name := clause.Name.(type) {
+	case *ast.ScopedPropertyAccessExpression:
+		trait = e.name(name.ScopeResolutionQualifier)
+		method = e.memberIdentifier(name.MemberName)
+	case *ast.QualifiedName:
+		method = e.node("Identifier", e.span(name), "name", e.nameString(name))
+	}
+	if clause.AsOrInsteadOfKeyword != nil && clause.AsOrInsteadOfKeyword.Kind == lexer.InsteadOfKeyword {
+		return e.node("Stmt_TraitUseAdaptation_Precedence", e.span(clause),
+			"trait", trait,
+			"method", method,
+			"insteadof", e.names(clause.TargetName))
+	}
+	var newModifier interface{}
+	if len(clause.Modifiers) > 0 {
+		newModifier = flags(clause.Modifiers)
+	}
+	var newName *Node
+	if name, ok := clause.TargetName.(*ast.QualifiedName); ok {
+		newName = e.node("Identifier", e.span(name), "name", e.nameString(name))
+	}
+	return e.node("Stmt_TraitUseAdaptation_Alias", e.span(clause),
+		"trait", trait,
+		"method", method,
+		"newModifier", newModifier,
+		"newName", newName)
+}
+
+// consts converts the elements of a const declaration to Const_ nodes.
+func (e *exporter) consts(list ast.Node) []*Node {
+	consts := []*Node{}
+	for _, element := range elements(list) {
+		if element, ok := element.(*ast.ConstElement); ok {
+			consts = append(consts, e.node("Const", e.span(element),
+				"name", e.identifier(element.Name),
+				"value", e.expression(element.Assignment)))
+		}
+	}
+	return consts
+}
+
+// params converts the parameters of a function.
+func (e *exporter) params(list ast.Node) []*Node {
+	params := []*Node{}
+	for _, element := range elements(list) {
+		parameter, ok := element.(*ast.Parameter)
+		if !ok {
+			continue
+		}
+		params = append(params, e.node("Param", e.span(parameter),
+			"attrGroups", []*Node{},
+			"flags", 0,
+			"type", e.typeNode(parameter.TypeDeclaration, parameter.QuestionToken),
+			"byRef", parameter.ByRefToken != nil,
+			"variadic", parameter.DotDotDotToken != nil,
+			"var", e.variable(parameter.VariableName),
+			"default", e.expression(parameter.Default)))
+	}
+	return params
+}
+
+// nameToken returns the token of the name of a function.
+func nameToken(name ast.NodeWithToken) *lexer.Token {
+	if name == nil {
+		return nil
+	}
+	return name.GetToken()
+}
+
+// elements returns the elements of a list node without its commas.
+func elements(list ast.Node) []ast.Node {
+	delimited, ok := list.(ast.DelimitedList)
+	if !ok {
+		if list == nil {
+			return nil
+		}
+		return []ast.Node{list}
+	}
+	var nodes []ast.Node
+	for _, element := range delimited.Elements() {
+		if isToken(element, lexer.CommaToken) {
+			continue
+		}
+		nodes = append(nodes, element)
+	}
+	return nodes
+}
+
+// isToken tells whether node is a token of kind.
+func isToken(node ast.Node, kind lexer.TokenKind) bool {
+	tokenNode, ok := node.(*ast.TokenNode)
+	return ok && tokenNode.Token.Kind == kind
+}
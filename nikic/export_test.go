@@ -0,0 +1,56 @@
+package nikic_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/emilioastarita/gphp/nikic"
+	"github.com/emilioastarita/gphp/parser"
+)
+
+func export(t *testing.T, sourceFileName string) []byte {
+	data, _ := ioutil.ReadFile(sourceFileName)
+	p := parser.Parser{}
+	sourceFile := p.ParseSourceFile(data, "")
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "    ")
+	if err := encoder.Encode(nikic.Export(sourceFile)); err != nil {
+		t.Fatalf("%s: %v", sourceFileName, err)
+	}
+	return buf.Bytes()
+}
+
+// TestCases compares the exports of the cases with their .json, written
+// by gphp parse --format=nikic and checked against PHP-Parser.
+func TestCases(t *testing.T) {
+	resultFiles, _ := filepath.Glob("cases/*.php.json")
+	if len(resultFiles) == 0 {
+		t.Fatal("no cases found")
+	}
+	for _, resultFile := range resultFiles {
+		t.Run(resultFile, func(t *testing.T) {
+			expected, _ := ioutil.ReadFile(resultFile)
+			sourceFileName := resultFile[:len(resultFile)-len(".json")]
+			if actual := export(t, sourceFileName); !bytes.Equal(actual, expected) {
+				t.Errorf("the export differs:\n%s", actual)
+			}
+		})
+	}
+}
+
+// TestExportCorpus exports the parser cases, the broken ones too, and
+// checks the json is valid.
+func TestExportCorpus(t *testing.T) {
+	sourceFiles, _ := filepath.Glob("../parser/cases/*.php")
+	recoveryFiles, _ := filepath.Glob("../parser/recovery/*.php")
+	for _, sourceFileName := range append(sourceFiles, recoveryFiles...) {
+		if !json.Valid(export(t, sourceFileName)) {
+			t.Errorf("%s: invalid json", sourceFileName)
+		}
+	}
+}
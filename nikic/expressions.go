@@ -0,0 +1,535 @@
+package nikic
+
+import (
+	"strings"
+
+	"github.com/emilioastarita/gphp/ast"
+	"github.com/emilioastarita/gphp/lexer"
+)
+
+// The node types of the binary operators and of the compound
+// assignments.
+var binaryOps = map[lexer.TokenKind]string{
+	lexer.AmpersandToken:                 "Expr_BinaryOp_BitwiseAnd",
+	lexer.BarToken:                       "Expr_BinaryOp_BitwiseOr",
+	lexer.CaretToken:                     "Expr_BinaryOp_BitwiseXor",
+	lexer.AmpersandAmpersandToken:        "Expr_BinaryOp_BooleanAnd",
+	lexer.BarBarToken:                    "Expr_BinaryOp_BooleanOr",
+	lexer.QuestionQuestionToken:          "Expr_BinaryOp_Coalesce",
+	lexer.DotToken:                       "Expr_BinaryOp_Concat",
+	lexer.SlashToken:                     "Expr_BinaryOp_Div",
+	lexer.EqualsEqualsToken:              "Expr_BinaryOp_Equal",
+	lexer.GreaterThanToken:               "Expr_BinaryOp_Greater",
+	lexer.GreaterThanEqualsToken:         "Expr_BinaryOp_GreaterOrEqual",
+	lexer.EqualsEqualsEqualsToken:        "Expr_BinaryOp_Identical",
+	lexer.AndKeyword:                     "Expr_BinaryOp_LogicalAnd",
+	lexer.OrKeyword:                      "Expr_BinaryOp_LogicalOr",
+	lexer.XorKeyword:                     "Expr_BinaryOp_LogicalXor",
+	lexer.MinusToken:                     "Expr_BinaryOp_Minus",
+	lexer.PercentToken:                   "Expr_BinaryOp_Mod",
+	lexer.AsteriskToken:                  "Expr_BinaryOp_Mul",
+	lexer.ExclamationEqualsToken:         "Expr_BinaryOp_NotEqual",
+	lexer.LessThanGreaterThanToken:       "Expr_BinaryOp_NotEqual",
+	lexer.ExclamationEqualsEqualsToken:   "Expr_BinaryOp_NotIdentical",
+	lexer.PlusToken:                      "Expr_BinaryOp_Plus",
+	lexer.AsteriskAsteriskToken:          "Expr_BinaryOp_Pow",
+	lexer.LessThanLessThanToken:          "Expr_BinaryOp_ShiftLeft",
+	lexer.GreaterThanGreaterThanToken:    "Expr_BinaryOp_ShiftRight",
+	lexer.LessThanToken:                  "Expr_BinaryOp_Smaller",
+	lexer.LessThanEqualsToken:            "Expr_BinaryOp_SmallerOrEqual",
+	lexer.LessThanEqualsGreaterThanToken: "Expr_BinaryOp_Spaceship",
+
+	lexer.AmpersandEqualsToken:              "Expr_AssignOp_BitwiseAnd",
+	lexer.BarEqualsToken:                    "Expr_AssignOp_BitwiseOr",
+	lexer.CaretEqualsToken:                  "Expr_AssignOp_BitwiseXor",
+	lexer.DotEqualsToken:                    "Expr_AssignOp_Concat",
+	lexer.SlashEqualsToken:                  "Expr_AssignOp_Div",
+	lexer.MinusEqualsToken:                  "Expr_AssignOp_Minus",
+	lexer.PercentEqualsToken:                "Expr_AssignOp_Mod",
+	lexer.AsteriskEqualsToken:               "Expr_AssignOp_Mul",
+	lexer.PlusEqualsToken:                   "Expr_AssignOp_Plus",
+	lexer.AsteriskAsteriskEqualsToken:       "Expr_AssignOp_Pow",
+	lexer.LessThanLessThanEqualsToken:       "Expr_AssignOp_ShiftLeft",
+	lexer.GreaterThanGreaterThanEqualsToken: "Expr_AssignOp_ShiftRight",
+}
+
+var unaryOps = map[lexer.TokenKind]string{
+	lexer.PlusToken:        "Expr_UnaryPlus",
+	lexer.MinusToken:       "Expr_UnaryMinus",
+	lexer.ExclamationToken: "Expr_BooleanNot",
+	lexer.TildeToken:       "Expr_BitwiseNot",
+}
+
+// The node types and kinds of the casts by the name of their type.
+var casts = map[string]struct {
+	nodeType string
+	kind     int
+}{
+	"int":     {"Expr_Cast_Int", 0},
+	"integer": {"Expr_Cast_Int", 0},
+	"bool":    {"Expr_Cast_Bool", 0},
+	"boolean": {"Expr_Cast_Bool", 0},
+	"double":  {"Expr_Cast_Double", 1},
+	"float":   {"Expr_Cast_Double", 2},
+	"real":    {"Expr_Cast_Double", 3},
+	"string":  {"Expr_Cast_String", 0},
+	"binary":  {"Expr_Cast_String", 0},
+	"array":   {"Expr_Cast_Array", 0},
+	"object":  {"Expr_Cast_Object", 0},
+	"unset":   {"Expr_Cast_Unset", 0},
+}
+
+// The types of Expr_Include.
+var includeTypes = map[lexer.TokenKind]int{
+	lexer.IncludeKeyword:     1,
+	lexer.IncludeOnceKeyword: 2,
+	lexer.RequireKeyword:     3,
+	lexer.RequireOnceKeyword: 4,
+}
+
+var magicConstants = map[string]string{
+	"__line__":      "Scalar_MagicConst_Line",
+	"__file__":      "Scalar_MagicConst_File",
+	"__dir__":       "Scalar_MagicConst_Dir",
+	"__function__":  "Scalar_MagicConst_Function",
+	"__class__":     "Scalar_MagicConst_Class",
+	"__trait__":     "Scalar_MagicConst_Trait",
+	"__method__":    "Scalar_MagicConst_Method",
+	"__namespace__": "Scalar_MagicConst_Namespace",
+}
+
+// builtinTypes are the types written as an Identifier instead of a Name.
+var builtinTypes = map[string]bool{
+	"bool": true, "int": true, "float": true, "string": true,
+	"iterable": true, "void": true, "object": true, "null": true,
+	"false": true, "mixed": true, "never": true,
+}
+
+// The kinds of Expr_Array and Expr_Exit.
+const (
+	arrayLong  = 1
+	arrayShort = 2
+	exitExit   = 1
+	exitDie    = 2
+)
+
+// expressions converts the expressions of a list node.
+func (e *exporter) expressions(list ast.Node) []*Node {
+	expressions := []*Node{}
+	for _, element := range elements(list) {
+		expressions = append(expressions, e.expression(element))
+	}
+	return expressions
+}
+
+// expression converts an expression, nil is null.
+func (e *exporter) expression(node ast.Node) *Node {
+	if node == nil {
+		return nil
+	}
+	s := e.span(node)
+	switch n := node.(type) {
+	case *ast.ParenthesizedExpression:
+		return e.expression(n.Expression)
+	case *ast.BracedExpression:
+		return e.expression(n.Expression)
+	case *ast.Variable:
+		if n.Dollar == nil {
+			if name, ok := n.Name.(*ast.TokenNode); ok {
+				return e.node("Expr_Variable", s, "name", strings.TrimPrefix(e.text(name.Token), "$"))
+			}
+		}
+		return e.node("Expr_Variable", s, "name", e.expression(n.Name))
+	case *ast.QualifiedName:
+		if nodeType, ok := magicConstants[strings.ToLower(e.nameString(n))]; ok && len(n.NameParts) == 1 {
+			return e.node(nodeType, s)
+		}
+		return e.node("Expr_ConstFetch", s, "name", e.name(n))
+	case *ast.ReservedWord:
+		return e.node("Expr_ConstFetch", s, "name", e.node("Name", s, "parts", []string{e.text(n.Child)}))
+	case *ast.NumericLiteral:
+		return e.number(n.Child)
+	case *ast.StringLiteral:
+		return e.stringLiteral(n, s)
+	case *ast.ArrayCreationExpression:
+		kind := arrayShort
+		if n.ArrayKeyword != nil {
+			kind = arrayLong
+		}
+		array := e.node("Expr_Array", s, "items", e.arrayItems(n.ArrayElements, false))
+		array.Attributes.Kind = kind
+		return array
+	case *ast.ListIntrinsicExpression:
+		return e.node("Expr_List", s, "items", e.arrayItems(n.ListElements, true))
+	case *ast.AssignmentExpression:
+		nodeType := "Expr_Assign"
+		if n.ByRef != nil {
+			nodeType = "Expr_AssignRef"
+		}
+		return e.node(nodeType, s, "var", e.assignable(n.LeftOperand), "expr", e.expression(n.RightOperand))
+	case *ast.BinaryExpression:
+		if n.Operator.Kind == lexer.InstanceOfKeyword {
+			return e.node("Expr_Instanceof", s, "expr", e.expression(n.LeftOperand), "class", e.class(n.RightOperand))
+		}
+		nodeType, ok := binaryOps[n.Operator.Kind]
+		if !ok {
+			return e.errorNode(s)
+		}
+		if strings.HasPrefix(nodeType, "Expr_AssignOp_") {
+			return e.node(nodeType, s, "var", e.expression(n.LeftOperand), "expr", e.expression(n.RightOperand))
+		}
+		return e.node(nodeType, s, "left", e.expression(n.LeftOperand), "right", e.expression(n.RightOperand))
+	case *ast.UnaryOpExpression:
+		nodeType, ok := unaryOps[n.Operator.Kind]
+		if !ok {
+			return e.errorNode(s)
+		}
+		return e.node(nodeType, s, "expr", e.expression(n.Operand))
+	case *ast.ErrorControlExpression:
+		return e.node("Expr_ErrorSuppress", s, "expr", e.expression(n.Operand))
+	case *ast.CastExpression:
+		name := strings.ToLower(strings.Trim(e.text(n.CastType), "( \t)"))
+		cast, ok := casts[name]
+		if !ok {
+			return e.errorNode(s)
+		}
+		node := e.node(cast.nodeType, s, "expr", e.expression(n.Operand))
+		node.Attributes.Kind = cast.kind
+		return node
+	case *ast.PrefixUpdateExpression:
+		nodeType := "Expr_PreInc"
+		if n.IncrementOrDecrementOperator.Kind == lexer.MinusMinusToken {
+			nodeType = "Expr_PreDec"
+		}
+		return e.node(nodeType, s, "var", e.expression(n.Operand))
+	case *ast.PostfixUpdateExpression:
+		nodeType := "Expr_PostInc"
+		if n.IncrementOrDecrementOperator.Kind == lexer.MinusMinusToken {
+			nodeType = "Expr_PostDec"
+		}
+		return e.node(nodeType, s, "var", e.expression(n.Operand))
+	case *ast.CloneExpression:
+		return e.node("Expr_Clone", s, "expr", e.expression(n.Expression))
+	case *ast.EmptyIntrinsicExpression:
+		return e.node("Expr_Empty", s, "expr", e.expression(n.Expression))
+	case *ast.IssetIntrinsicExpression:
+		return e.node("Expr_Isset", s, "vars", e.expressions(n.Expressions))
+	case *ast.EvalIntrinsicExpression:
+		return e.node("Expr_Eval", s, "expr", e.expression(n.Expression))
+	case *ast.ExitIntrinsicExpression:
+		kind := exitExit
+		if strings.ToLower(e.text(n.ExitOrDieKeyword)) == "die" {
+			kind = exitDie
+		}
+		node := e.node("Expr_Exit", s, "expr", e.expression(n.Expression))
+		node.Attributes.Kind = kind
+		return node
+	case *ast.PrintIntrinsicExpression:
+		return e.node("Expr_Print", s, "expr", e.expression(n.Expression))
+	case *ast.ScriptInclusionExpression:
+		return e.node("Expr_Include", s, "expr", e.expression(n.Expression), "type", includeTypes[n.RequireOrIncludeKeyword.Kind])
+	case *ast.CallExpression:
+		args := e.args(n.ArgumentExpressionList)
+		switch callable := n.CallableExpression.(type) {
+		case *ast.MemberAccessExpression:
+			return e.node("Expr_MethodCall", s,
+				"var", e.expression(callable.DereferencableExpression),
+				"name", e.memberName(callable.MemberName),
+				"args", args)
+		case *ast.ScopedPropertyAccessExpression:
+			return e.node("Expr_StaticCall", s,
+				"class", e.class(callable.ScopeResolutionQualifier),
+				"name", e.memberName(callable.MemberName),
+				"args", args)
+		case *ast.QualifiedName:
+			return e.node("Expr_FuncCall", s, "name", e.name(callable), "args", args)
+		}
+		return e.node("Expr_FuncCall", s, "name", e.expression(n.CallableExpression), "args", args)
+	case *ast.MemberAccessExpression:
+		return e.node("Expr_PropertyFetch", s,
+			"var", e.expression(n.DereferencableExpression),
+			"name", e.memberName(n.MemberName))
+	case *ast.ScopedPropertyAccessExpression:
+		if variable, ok := n.MemberName.(*ast.Variable); ok {
+			var name *Node
+			if variable.Dollar == nil {
+				name = e.varLikeIdentifier(variable)
+			} else {
+				name = e.expression(variable.Name)
+			}
+			return e.node("Expr_StaticPropertyFetch", s,
+				"class", e.class(n.ScopeResolutionQualifier),
+				"name", name)
+		}
+		return e.node("Expr_ClassConstFetch", s,
+			"class", e.class(n.ScopeResolutionQualifier),
+			"name", e.memberName(n.MemberName))
+	case *ast.SubscriptExpression:
+		return e.node("Expr_ArrayDimFetch", s,
+			"var", e.expression(n.PostfixExpression),
+			"dim", e.expression(n.AccessExpression))
+	case *ast.ObjectCreationExpression:
+		var class *Node
+		if isToken(n.ClassTypeDesignator, lexer.ClassKeyword) {
+			s := e.span(n.ClassTypeDesignator).merge(e.span(n.ClassMembers))
+			class = e.classDeclaration(s, 0, nil, n.ClassBaseClause, n.ClassInterfaceClause, n.ClassMembers)
+		} else {
+			class = e.class(n.ClassTypeDesignator)
+		}
+		return e.node("Expr_New", s, "class", class, "args", e.args(n.ArgumentExpressionList))
+	case *ast.AnonymousFunctionCreationExpression:
+		uses := []*Node{}
+		if clause, ok := n.AnonymousFunctionUseClause.(*ast.AnonymousFunctionUseClause); ok {
+			for _, element := range elements(clause.UseVariableNameList) {
+				if name, ok := element.(*ast.UseVariableName); ok {
+					uses = append(uses, e.node("Expr_ClosureUse", e.span(name),
+						"var", e.variable(name.VariableName),
+						"byRef", name.ByRef != nil))
+				}
+			}
+		}
+		return e.node("Expr_Closure", s,
+			"attrGroups", []*Node{},
+			"static", n.StaticModifier != nil,
+			"byRef", n.ByRefToken != nil,
+			"params", e.params(n.Parameters),
+			"uses", uses,
+			"returnType", e.typeNode(n.ReturnType, n.FunctionReturnType.QuestionToken),
+			"stmts", e.statementsOf(n.CompoundStatementOrSemicolon))
+	case *ast.TernaryExpression:
+		return e.node("Expr_Ternary", s,
+			"cond", e.expression(n.Condition),
+			"if", e.expression(n.IfExpression),
+			"else", e.expression(n.ElseExpression))
+	case *ast.YieldExpression:
+		element, _ := n.ArrayElement.(*ast.ArrayElement)
+		if n.YieldOrYieldFromKeyword.Kind == lexer.YieldFromKeyword {
+			var expression ast.Node
+			if element != nil {
+				expression = element.ElementValue
+			}
+			return e.node("Expr_YieldFrom", s, "expr", e.expression(expression))
+		}
+		var key, value ast.Node
+		if element != nil {
+			key, value = element.ElementKey, element.ElementValue
+		}
+		return e.node("Expr_Yield", s, "key", e.expression(key), "value", e.expression(value))
+	case *ast.TokenNode:
+		switch n.Token.Kind {
+		case lexer.Name, lexer.StaticKeyword:
+			return e.node("Expr_ConstFetch", s, "name", e.node("Name", s, "parts", []string{e.text(n.Token)}))
+		case lexer.VariableName:
+			return e.node("Expr_Variable", s, "name", strings.TrimPrefix(e.text(n.Token), "$"))
+		}
+	}
+	// missing and skipped tokens and the statements that aren't
+	// expressions, like an echo or an unset in a condition
+	return e.errorNode(s)
+}
+
+func (e *exporter) errorNode(s span) *Node {
+	return e.node("Expr_Error", s)
+}
+
+// assignable converts the left side of an assignment or the value of a
+// foreach, where an array is a list.
+func (e *exporter) assignable(node ast.Node) *Node {
+	array, ok := node.(*ast.ArrayCreationExpression)
+	if !ok || array.ArrayKeyword != nil {
+		return e.expression(node)
+	}
+	return e.node("Expr_List", e.span(array), "items", e.arrayItems(array.ArrayElements, true))
+}
+
+// arrayItems converts the elements of an array or of a list. The empty
+// elements of a list are nulls, the values of its elements are lists too.
+func (e *exporter) arrayItems(list ast.Node, isList bool) []*Node {
+	items := []*Node{}
+	if list == nil {
+		return items
+	}
+	// an element before every comma and one after the last
+	var element ast.Node
+	for _, child := range list.(ast.DelimitedList).Elements() {
+		if isToken(child, lexer.CommaToken) {
+			items = append(items, e.arrayItem(element, isList))
+			element = nil
+			continue
+		}
+		element = child
+	}
+	if element != nil {
+		items = append(items, e.arrayItem(element, isList))
+	}
+	return items
+}
+
+func (e *exporter) arrayItem(node ast.Node, isList bool) *Node {
+	element, ok := node.(*ast.ArrayElement)
+	if !ok {
+		if node == nil {
+			return nil
+		}
+		return e.expression(node)
+	}
+	value := e.expression(element.ElementValue)
+	if isList {
+		value = e.assignable(element.ElementValue)
+	}
+	return e.node("Expr_ArrayItem", e.span(element),
+		"key", e.expression(element.ElementKey),
+		"value", value,
+		"byRef", element.ByRef != nil,
+		"unpack", false)
+}
+
+// args converts the arguments of a call.
+func (e *exporter) args(list ast.Node) []*Node {
+	args := []*Node{}
+	for _, element := range elements(list) {
+		argument, ok := element.(*ast.ArgumentExpression)
+		if !ok {
+			args = append(args, e.expression(element))
+			continue
+		}
+		args = append(args, e.node("Arg", e.span(argument),
+			"name", nil,
+			"value", e.expression(argument.Expression),
+			"byRef", argument.ByRefToken != nil,
+			"unpack", argument.DotDotDotToken != nil))
+	}
+	return args
+}
+
+// class converts the class of a new, a static access or an instanceof, a
+// Name or an expression.
+func (e *exporter) class(node ast.Node) *Node {
+	switch n := node.(type) {
+	case *ast.QualifiedName:
+		return e.name(n)
+	case *ast.TokenNode:
+		if n.Token.Kind == lexer.StaticKeyword || n.Token.Kind == lexer.Name {
+			return e.node("Name", e.span(n), "parts", []string{e.text(n.Token)})
+		}
+	}
+	return e.expression(node)
+}
+
+// memberName converts the name of a property, a method or a constant, an
+// Identifier or an expression.
+func (e *exporter) memberName(node ast.Node) *Node {
+	if tokenNode, ok := node.(*ast.TokenNode); ok {
+		return e.identifier(tokenNode.Token)
+	}
+	return e.expression(node)
+}
+
+// memberIdentifier converts the name of a method of a trait adaptation.
+func (e *exporter) memberIdentifier(node ast.Node) *Node {
+	if tokenNode, ok := node.(*ast.TokenNode); ok {
+		return e.identifier(tokenNode.Token)
+	}
+	return nil
+}
+
+// identifier converts a name token, nil for a missing one.
+func (e *exporter) identifier(token *lexer.Token) *Node {
+	if token == nil || token.Cat == lexer.TokenCatMissing {
+		return nil
+	}
+	return e.node("Identifier", tokenSpan(token), "name", e.text(token))
+}
+
+// varLikeIdentifier converts the name of a property.
+func (e *exporter) varLikeIdentifier(variable *ast.Variable) *Node {
+	name, ok := variable.Name.(*ast.TokenNode)
+	if !ok {
+		return nil
+	}
+	return e.node("VarLikeIdentifier", e.span(variable), "name", strings.TrimPrefix(e.text(name.Token), "$"))
+}
+
+// variable converts a variable name token.
+func (e *exporter) variable(token *lexer.Token) *Node {
+	if token == nil || token.Cat == lexer.TokenCatMissing {
+		return nil
+	}
+	return e.node("Expr_Variable", tokenSpan(token), "name", strings.TrimPrefix(e.text(token), "$"))
+}
+
+// nameString returns the parts of a qualified name joined by backslashes,
+// without its specifiers.
+func (e *exporter) nameString(name *ast.QualifiedName) string {
+	return strings.Join(e.nameParts(name), `\`)
+}
+
+func (e *exporter) nameParts(name *ast.QualifiedName) []string {
+	parts := []string{}
+	for _, part := range name.NameParts {
+		if tokenNode, ok := part.(*ast.TokenNode); ok && tokenNode.Token.Kind != lexer.BackslashToken {
+			parts = append(parts, e.text(tokenNode.Token))
+		}
+	}
+	return parts
+}
+
+// name converts a qualified name to a Name, a Name_FullyQualified or a
+// Name_Relative.
+func (e *exporter) name(node ast.Node) *Node {
+	name, ok := node.(*ast.QualifiedName)
+	if !ok {
+		return nil
+	}
+	nodeType := "Name"
+	if name.GlobalSpecifier != nil {
+		nodeType = "Name_FullyQualified"
+	} else if name.RelativeSpecifier != nil {
+		nodeType = "Name_Relative"
+	}
+	return e.node(nodeType, e.span(name), "parts", e.nameParts(name))
+}
+
+// names converts a list of qualified names.
+func (e *exporter) names(list ast.Node) []*Node {
+	names := []*Node{}
+	for _, element := range elements(list) {
+		if name := e.name(element); name != nil {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// typeNode converts the type of a parameter or the return type of a
+// function: an Identifier for the builtin types, a Name for the classes
+// and a NullableType around them when question isn't nil.
+func (e *exporter) typeNode(node ast.Node, question *lexer.Token) *Node {
+	var t *Node
+	switch n := node.(type) {
+	case *ast.QualifiedName:
+		lower := strings.ToLower(e.nameString(n))
+		if n.GlobalSpecifier == nil && n.RelativeSpecifier == nil && len(n.NameParts) == 1 && builtinTypes[lower] {
+			t = e.node("Identifier", e.span(n), "name", lower)
+		} else {
+			t = e.name(n)
+		}
+	case *ast.TokenNode:
+		t = e.builtinType(n.Token)
+	case *ast.ReservedWord:
+		t = e.builtinType(n.Child)
+	}
+	if t == nil || question == nil {
+		return t
+	}
+	return e.node("NullableType", tokenSpan(question).merge(e.span(node)), "type", t)
+}
+
+// builtinType converts the token of a builtin type, like array or int.
+func (e *exporter) builtinType(token *lexer.Token) *Node {
+	t := e.identifier(token)
+	if t != nil {
+		t.SubNodes[0].Value = strings.ToLower(e.text(token))
+	}
+	return t
+}
@@ -0,0 +1,191 @@
+package nikic
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Node is a node of PHP-Parser. Its subnodes are nil, bools, ints,
+// float64s, strings, []string, *Node or []*Node, written in json in their
+// order after the nodeType and before the attributes.
+type Node struct {
+	Type       string
+	SubNodes   []SubNode
+	Attributes Attributes
+}
+
+// SubNode is a named child of a node.
+type SubNode struct {
+	Name  string
+	Value interface{}
+}
+
+// Attributes are the positions of a node: its first and last line,
+// counted from 1, the offsets of its first and last byte and the comments
+// before it. They are -1 for a node without source, like the Expr_Error
+// of a missing expression. Kind and DocLabel are the attributes of the
+// literals that tell how they were written.
+type Attributes struct {
+	StartLine    int
+	EndLine      int
+	StartFilePos int
+	EndFilePos   int
+	Comments     []Comment
+	Kind         int
+	DocLabel     string
+}
+
+// Comment is a comment before a node, a Comment_Doc for the /** */ ones.
+type Comment struct {
+	Doc        bool
+	Text       string
+	Line       int
+	FilePos    int
+	EndLine    int
+	EndFilePos int
+}
+
+// Get returns the value of the subnode name, nil when the node hasn't got
+// it.
+func (n *Node) Get(name string) interface{} {
+	for _, sub := range n.SubNodes {
+		if sub.Name == name {
+			return sub.Value
+		}
+	}
+	return nil
+}
+
+// MarshalJSON writes the node as the jsonSerialize of PHP-Parser does.
+func (n *Node) MarshalJSON() ([]byte, error) {
+	var b bytes.Buffer
+	n.writeJSON(&b)
+	return b.Bytes(), nil
+}
+
+func (n *Node) writeJSON(b *bytes.Buffer) {
+	if n == nil {
+		b.WriteString("null")
+		return
+	}
+	b.WriteString(`{"nodeType":`)
+	writeString(b, n.Type)
+	for _, sub := range n.SubNodes {
+		b.WriteByte(',')
+		writeString(b, sub.Name)
+		b.WriteByte(':')
+		writeValue(b, sub.Value)
+	}
+	b.WriteString(`,"attributes":`)
+	n.Attributes.writeJSON(b)
+	b.WriteByte('}')
+}
+
+func (a *Attributes) writeJSON(b *bytes.Buffer) {
+	b.WriteString(`{"startLine":` + strconv.Itoa(a.StartLine))
+	b.WriteString(`,"startFilePos":` + strconv.Itoa(a.StartFilePos))
+	b.WriteString(`,"endLine":` + strconv.Itoa(a.EndLine))
+	b.WriteString(`,"endFilePos":` + strconv.Itoa(a.EndFilePos))
+	if len(a.Comments) > 0 {
+		b.WriteString(`,"comments":[`)
+		for i, comment := range a.Comments {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			comment.writeJSON(b)
+		}
+		b.WriteByte(']')
+	}
+	if a.Kind != 0 {
+		b.WriteString(`,"kind":` + strconv.Itoa(a.Kind))
+	}
+	if a.DocLabel != "" {
+		b.WriteString(`,"docLabel":`)
+		writeString(b, a.DocLabel)
+	}
+	b.WriteByte('}')
+}
+
+func (c *Comment) writeJSON(b *bytes.Buffer) {
+	if c.Doc {
+		b.WriteString(`{"nodeType":"Comment_Doc","text":`)
+	} else {
+		b.WriteString(`{"nodeType":"Comment","text":`)
+	}
+	writeString(b, c.Text)
+	// the tokens of php aren't the tokens of the lexer, their positions
+	// are unknown
+	b.WriteString(`,"line":` + strconv.Itoa(c.Line))
+	b.WriteString(`,"filePos":` + strconv.Itoa(c.FilePos))
+	b.WriteString(`,"tokenPos":-1`)
+	b.WriteString(`,"endLine":` + strconv.Itoa(c.EndLine))
+	b.WriteString(`,"endFilePos":` + strconv.Itoa(c.EndFilePos))
+	b.WriteString(`,"endTokenPos":-1}`)
+}
+
+func writeValue(b *bytes.Buffer, value interface{}) {
+	switch value := value.(type) {
+	case nil:
+		b.WriteString("null")
+	case bool:
+		b.WriteString(strconv.FormatBool(value))
+	case int:
+		b.WriteString(strconv.Itoa(value))
+	case float64:
+		b.WriteString(formatFloat(value))
+	case string:
+		writeString(b, value)
+	case []string:
+		b.WriteByte('[')
+		for i, s := range value {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			writeString(b, s)
+		}
+		b.WriteByte(']')
+	case *Node:
+		value.writeJSON(b)
+	case []*Node:
+		b.WriteByte('[')
+		for i, node := range value {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			node.writeJSON(b)
+		}
+		b.WriteByte(']')
+	default:
+		panic("nikic: unexpected subnode value")
+	}
+}
+
+func writeString(b *bytes.Buffer, s string) {
+	encoder := json.NewEncoder(b)
+	encoder.SetEscapeHTML(false)
+	encoder.Encode(s)
+	// Encode ends the value with a newline
+	b.Truncate(b.Len() - 1)
+}
+
+// formatFloat writes a float as json_encode does, with a fraction or an
+// exponent so it's read back as a float. Infinity, the value of the too
+// big literals, has no json and is written as a number that overflows.
+func formatFloat(f float64) string {
+	if math.IsInf(f, 1) {
+		return "1.0e+1000"
+	}
+	abs := math.Abs(f)
+	format := byte('f')
+	if abs != 0 && (abs < 1e-6 || abs >= 1e15) {
+		format = 'e'
+	}
+	s := strconv.FormatFloat(f, format, -1, 64)
+	if !strings.ContainsAny(s, ".e") {
+		s += ".0"
+	}
+	return s
+}
@@ -0,0 +1,316 @@
+package nikic
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/emilioastarita/gphp/ast"
+	"github.com/emilioastarita/gphp/lexer"
+)
+
+// The kinds of Scalar_LNumber.
+const (
+	numberBin = 2
+	numberOct = 8
+	numberDec = 10
+	numberHex = 16
+)
+
+// The kinds of Scalar_String and Scalar_Encapsed.
+const (
+	stringSingleQuoted = 1
+	stringDoubleQuoted = 2
+	stringHeredoc      = 3
+	stringNowdoc       = 4
+)
+
+// literal converts the token of a number or of a string without
+// interpolations, like the value of a declare.
+func (e *exporter) literal(token *lexer.Token) *Node {
+	if token != nil && token.Kind == lexer.StringLiteralToken {
+		return e.quotedString(token)
+	}
+	return e.number(token)
+}
+
+// number converts a number to a Scalar_LNumber, or to a Scalar_DNumber
+// for the floats and the integers too big for an int64.
+func (e *exporter) number(token *lexer.Token) *Node {
+	s := tokenSpan(token)
+	if token == nil || token.Cat == lexer.TokenCatMissing {
+		return e.errorNode(s)
+	}
+	text := strings.Replace(e.text(token), "_", "", -1)
+	switch token.Kind {
+	case lexer.FloatingLiteralToken:
+		f, _ := strconv.ParseFloat(text, 64)
+		return e.node("Scalar_DNumber", s, "value", f)
+	case lexer.IntegerLiteralToken:
+	default:
+		// the invalid octal, hexadecimal and binary literals
+		return e.errorNode(s)
+	}
+	kind, digits := numberDec, text
+	lower := strings.ToLower(text)
+	switch {
+	case strings.HasPrefix(lower, "0x"):
+		kind, digits = numberHex, text[2:]
+	case strings.HasPrefix(lower, "0b"):
+		kind, digits = numberBin, text[2:]
+	case len(text) > 1 && text[0] == '0':
+		kind, digits = numberOct, text[1:]
+	}
+	value, err := strconv.ParseInt(digits, kind, 64)
+	if err != nil {
+		var f float64
+		if kind == numberDec {
+			f, _ = strconv.ParseFloat(digits, 64)
+		} else {
+			for _, digit := range strings.ToLower(digits) {
+				d, _ := strconv.ParseInt(string(digit), kind, 64)
+				f = f*float64(kind) + float64(d)
+			}
+		}
+		return e.node("Scalar_DNumber", s, "value", f)
+	}
+	number := e.node("Scalar_LNumber", s, "value", int(value))
+	number.Attributes.Kind = kind
+	return number
+}
+
+// quotedString converts a single or double quoted string without
+// interpolations.
+func (e *exporter) quotedString(token *lexer.Token) *Node {
+	text := e.text(token)
+	if len(text) > 0 && (text[0] == 'b' || text[0] == 'B') {
+		text = text[1:]
+	}
+	if len(text) == 0 {
+		return e.errorNode(tokenSpan(token))
+	}
+	quote := text[0]
+	text = text[1:]
+	if len(text) > 0 && text[len(text)-1] == quote {
+		text = text[:len(text)-1]
+	}
+	kind, value := stringDoubleQuoted, ""
+	if quote == '\'' {
+		kind, value = stringSingleQuoted, unescapeSingleQuoted(text)
+	} else {
+		value = unescape(text, quote)
+	}
+	node := e.node("Scalar_String", tokenSpan(token), "value", value)
+	node.Attributes.Kind = kind
+	return node
+}
+
+// stringLiteral converts a string to a Scalar_String, to a Scalar_Encapsed
+// when it has interpolations or to an Expr_ShellExec for the backticks.
+func (e *exporter) stringLiteral(n *ast.StringLiteral, s span) *Node {
+	if token, ok := n.Child.(*lexer.Token); ok && token.Kind == lexer.StringLiteralToken {
+		return e.quotedString(token)
+	}
+	parts, _ := n.Child.([]ast.Node)
+	if n.StartQuote == nil {
+		// the unquoted key of an array in a string, "$a[key]"
+		text := ""
+		if token, ok := n.Child.(*lexer.Token); ok {
+			text = e.text(token)
+		}
+		for _, part := range parts {
+			if tokenNode, ok := part.(*ast.TokenNode); ok {
+				text += e.text(tokenNode.Token)
+			}
+		}
+		return e.node("Scalar_String", s, "value", text)
+	}
+
+	kind, label, quote := stringDoubleQuoted, "", byte('"')
+	switch n.StartQuote.Kind {
+	case lexer.BacktickToken:
+		return e.node("Expr_ShellExec", s, "parts", e.encapsedParts(parts, '`', false))
+	case lexer.HeredocStart:
+		kind, quote = stringHeredoc, 0
+		label = strings.Trim(e.text(n.StartQuote), "<> \t\r\n")
+		if strings.HasPrefix(label, "'") {
+			kind = stringNowdoc
+		}
+		label = strings.Trim(label, `'"`)
+		parts = e.trimHeredoc(parts)
+	case lexer.DoubleQuoteToken:
+		if len(parts) > 0 && e.text(n.StartQuote) != `"` {
+			// the lexer splits the quote of a b"" string from its b
+			if first, ok := parts[0].(*ast.TokenNode); ok && strings.HasPrefix(e.text(first.Token), `"`) {
+				token := *first.Token
+				token.Start++
+				parts = append([]ast.Node{&ast.TokenNode{Token: &token}}, parts[1:]...)
+			}
+		}
+	}
+
+	encapsedParts := e.encapsedParts(parts, quote, kind == stringNowdoc)
+	var node *Node
+	if value, ok := joinParts(encapsedParts); ok {
+		node = e.node("Scalar_String", s, "value", value)
+	} else {
+		node = e.node("Scalar_Encapsed", s, "parts", encapsedParts)
+	}
+	node.Attributes.Kind = kind
+	node.Attributes.DocLabel = label
+	return node
+}
+
+// trimHeredoc removes the line feed before the closing label of a
+// heredoc from its last part.
+func (e *exporter) trimHeredoc(parts []ast.Node) []ast.Node {
+	if len(parts) == 0 {
+		return parts
+	}
+	last, ok := parts[len(parts)-1].(*ast.TokenNode)
+	if !ok || last.Token.Kind != lexer.EncapsedAndWhitespace {
+		return parts
+	}
+	text := e.text(last.Token)
+	trimmed := strings.TrimSuffix(text, "\n")
+	trimmed = strings.TrimSuffix(trimmed, "\r")
+	if trimmed == "" {
+		return parts[:len(parts)-1]
+	}
+	token := *last.Token
+	token.Length -= len(text) - len(trimmed)
+	return append(parts[:len(parts)-1:len(parts)-1], &ast.TokenNode{Token: &token})
+}
+
+// encapsedParts converts the parts of a string with interpolations, the
+// strings between the expressions are Scalar_EncapsedStringParts.
+func (e *exporter) encapsedParts(parts []ast.Node, quote byte, raw bool) []*Node {
+	nodes := []*Node{}
+	for _, part := range parts {
+		tokenNode, ok := part.(*ast.TokenNode)
+		if !ok {
+			nodes = append(nodes, e.expression(part))
+			continue
+		}
+		if tokenNode.Token.Kind != lexer.EncapsedAndWhitespace {
+			// the braces around the expressions
+			continue
+		}
+		value := e.text(tokenNode.Token)
+		if !raw {
+			value = unescape(value, quote)
+		}
+		nodes = append(nodes, e.node("Scalar_EncapsedStringPart", tokenSpan(tokenNode.Token), "value", value))
+	}
+	return nodes
+}
+
+// joinParts returns the value of a string whose parts are only strings.
+func joinParts(parts []*Node) (string, bool) {
+	var b strings.Builder
+	for _, part := range parts {
+		if part.Type != "Scalar_EncapsedStringPart" {
+			return "", false
+		}
+		b.WriteString(part.SubNodes[0].Value.(string))
+	}
+	return b.String(), true
+}
+
+// unescapeSingleQuoted evaluates the \\ and \' of a single quoted string.
+func unescapeSingleQuoted(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) && (s[i+1] == '\\' || s[i+1] == '\'') {
+			i++
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
+
+var escapes = map[byte]byte{
+	'\\': '\\', '$': '$', 'n': '\n', 't': '\t', 'r': '\r',
+	'f': '\f', 'v': '\v', 'e': 0x1b,
+}
+
+// unescape evaluates the escape sequences of a double quoted string, a
+// heredoc or a shell command. quote is the escaped quote, 0 for the
+// heredocs. Unknown sequences are kept as they are.
+func unescape(s string, quote byte) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i+1 == len(s) {
+			b.WriteByte(s[i])
+			continue
+		}
+		c := s[i+1]
+		if c == quote && quote != 0 {
+			b.WriteByte(quote)
+			i++
+			continue
+		}
+		if escaped, ok := escapes[c]; ok {
+			b.WriteByte(escaped)
+			i++
+			continue
+		}
+		switch {
+		case c >= '0' && c <= '7':
+			end := i + 1
+			for end < len(s) && end < i+4 && s[end] >= '0' && s[end] <= '7' {
+				end++
+			}
+			n, _ := strconv.ParseUint(s[i+1:end], 8, 16)
+			b.WriteByte(byte(n))
+			i = end - 1
+			continue
+		case (c == 'x' || c == 'X') && i+2 < len(s) && isHex(s[i+2]):
+			end := i + 3
+			if end < len(s) && isHex(s[end]) {
+				end++
+			}
+			n, _ := strconv.ParseUint(s[i+2:end], 16, 8)
+			b.WriteByte(byte(n))
+			i = end - 1
+			continue
+		case c == 'u' && i+2 < len(s) && s[i+2] == '{':
+			end := strings.IndexByte(s[i+3:], '}')
+			if end > 0 {
+				n, err := strconv.ParseUint(s[i+3:i+3+end], 16, 32)
+				if encoded, ok := encodeCodePoint(n); ok && err == nil {
+					b.WriteString(encoded)
+					i += 3 + end
+					continue
+				}
+			}
+		}
+		b.WriteByte('\\')
+	}
+	return b.String()
+}
+
+func isHex(c byte) bool {
+	return c >= '0' && c <= '9' || c >= 'a' && c <= 'f' || c >= 'A' && c <= 'F'
+}
+
+// encodeCodePoint encodes a code point in utf-8 as php does, the
+// surrogates included.
+func encodeCodePoint(n uint64) (string, bool) {
+	switch {
+	case n <= 0x7f:
+		return string([]byte{byte(n)}), true
+	case n <= 0x7ff:
+		return string([]byte{0xc0 | byte(n>>6), 0x80 | byte(n&0x3f)}), true
+	case n <= 0xffff:
+		return string([]byte{0xe0 | byte(n>>12), 0x80 | byte(n>>6&0x3f), 0x80 | byte(n&0x3f)}), true
+	case n <= 0x1fffff:
+		return string([]byte{0xf0 | byte(n>>18), 0x80 | byte(n>>12&0x3f), 0x80 | byte(n>>6&0x3f), 0x80 | byte(n&0x3f)}), true
+	}
+	return "", false
+}
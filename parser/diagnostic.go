@@ -0,0 +1,16 @@
+package parser
+
+import "github.com/emilioastarita/gphp/lexer"
+
+// The kinds of the diagnostics of the parser, after the lexical ones of
+// the lexer package.
+const (
+	// DiagnosticUnexpectedToken is reported for the tokens left after a
+	// fragment.
+	DiagnosticUnexpectedToken = lexer.DiagnosticParserKinds + iota
+	// reported when the parse stops before the end of the source, see
+	// Options
+	DiagnosticMaxDepthExceeded
+	DiagnosticMaxTokensExceeded
+	DiagnosticParseCanceled
+)
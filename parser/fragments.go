@@ -0,0 +1,68 @@
+package parser
+
+import (
+	"context"
+
+	"github.com/emilioastarita/gphp/ast"
+	"github.com/emilioastarita/gphp/lexer"
+)
+
+// ParseExpression parses a single expression, e.g. `$a + 1`. The source
+// has no <?php tag and offsets of tokens are relative to it.
+func (p *Parser) ParseExpression(source []byte) (ast.Node, []lexer.Diagnostic) {
+	defer p.startFragment(source)()
+	expression := p.parseExpression(nil, true)
+	return expression, p.endFragment()
+}
+
+// ParseStatements parses a list of statements like the body of a function.
+func (p *Parser) ParseStatements(source []byte) ([]ast.Node, []lexer.Diagnostic) {
+	defer p.startFragment(source)()
+	statements := p.parseList(nil, BlockStatements)
+	return statements, p.endFragment()
+}
+
+// ParseClassMembers parses the members of a class without its braces.
+func (p *Parser) ParseClassMembers(source []byte) ([]ast.Node, []lexer.Diagnostic) {
+	defer p.startFragment(source)()
+	members := p.parseList(nil, ClassMembers)
+	return members, p.endFragment()
+}
+
+// ParseType parses a parameter or return type like `?Foo\Bar`, the colon
+// token of the returned type is always nil.
+func (p *Parser) ParseType(source []byte) (*ast.FunctionReturnType, []lexer.Diagnostic) {
+	defer p.startFragment(source)()
+	returnType := &ast.FunctionReturnType{}
+	returnType.QuestionToken = p.eatOptional1(lexer.QuestionToken)
+	returnType.ReturnType = p.parseReturnTypeDeclaration(nil)
+	return returnType, p.endFragment()
+}
+
+// startFragment starts the parse of a fragment, the returned function
+// must be called when it is parsed.
+func (p *Parser) startFragment(source []byte) context.CancelFunc {
+	cancel := p.begin(context.Background())
+	p.stream = &lexer.TokensStream{Options: p.options.Lexer}
+	p.stream.SourceFragment(source)
+	p.lex()
+	p.reuse = nil
+	p.start()
+	return cancel
+}
+
+// endFragment returns the diagnostics of the lexer and reports the tokens
+// the fragment parser did not consume.
+func (p *Parser) endFragment() []lexer.Diagnostic {
+	diagnostics := append([]lexer.Diagnostic(nil), p.Diagnostics()...)
+	if p.token.Kind != lexer.EndOfFileToken {
+		end := p.stream.Tokens[p.stream.EofPos].FullStart
+		diagnostics = append(diagnostics, lexer.Diagnostic{
+			Kind:    DiagnosticUnexpectedToken,
+			Start:   p.token.Start,
+			Length:  end - p.token.Start,
+			Message: "Unexpected '" + p.token.Text(p.source) + "', expected end of input",
+		})
+	}
+	return diagnostics
+}
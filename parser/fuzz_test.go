@@ -0,0 +1,74 @@
+//go:build go1.18
+// +build go1.18
+
+package parser
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/emilioastarita/gphp/ast"
+	"github.com/emilioastarita/gphp/lexer"
+)
+
+// fuzzTimeLimit is the time given to parse an input, far more than what a
+// linear parse of the inputs of the fuzzer takes.
+const fuzzTimeLimit = 2 * time.Second
+
+func FuzzParser(f *testing.F) {
+	sourceFiles, _ := filepath.Glob("cases/*.php")
+	for _, sourceFile := range sourceFiles {
+		data, _ := ioutil.ReadFile(sourceFile)
+		f.Add(data)
+	}
+	f.Fuzz(func(t *testing.T, source []byte) {
+		for _, opts := range []Options{{}, {IndentRecovery: true}} {
+			checkParse(t, source, opts)
+		}
+	})
+}
+
+// checkParse parses source with opts, the parse must terminate and keep
+// every token of the source unless it stops at a limit.
+func checkParse(t *testing.T, source []byte, opts Options) {
+	done := make(chan *ast.SourceFileNode)
+	p := New(opts)
+	go func() {
+		done <- p.ParseSourceFile(source, "")
+	}()
+	var sourceFile *ast.SourceFileNode
+	select {
+	case sourceFile = <-done:
+	case <-time.After(fuzzTimeLimit):
+		t.Fatalf("parse takes more than %v", fuzzTimeLimit)
+	}
+	if p.aborted {
+		// the code past a limit is missing from the tree
+		return
+	}
+
+	// the tokens of the tree, skipped ones included, are the tokens of the
+	// source
+	var tokens []*lexer.Token
+	forEachToken(sourceFile, func(token *lexer.Token) {
+		if token.Cat != lexer.TokenCatMissing {
+			tokens = append(tokens, token)
+		}
+	})
+	sort.SliceStable(tokens, func(i, j int) bool { return tokens[i].FullStart < tokens[j].FullStart })
+	pos := 0
+	var text []byte
+	for _, token := range tokens {
+		if token.FullStart != pos || token.Start < token.FullStart || token.FullStart+token.Length > len(source) {
+			t.Fatalf("token %+v out of place, expected full start %d in %d bytes", token, pos, len(source))
+		}
+		text = append(text, token.FullText(source)...)
+		pos = token.FullStart + token.Length
+	}
+	if string(text) != string(source) {
+		t.Fatalf("tree tokens don't reproduce the source:\n%q\n%q", text, source)
+	}
+}
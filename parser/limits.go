@@ -0,0 +1,117 @@
+package parser
+
+import (
+	"context"
+
+	"github.com/emilioastarita/gphp/lexer"
+)
+
+// DefaultMaxDepth is the maximum nesting of statements and expressions
+// when Options.MaxDepth is 0. Go stacks grow, this limit keeps them well
+// below their maximum size.
+const DefaultMaxDepth = 10000
+
+// checkContextEvery is the number of limit checks between two checks of
+// the context.
+const checkContextEvery = 256
+
+// begin starts a parse, and the lexing before it, with the limits of the
+// options. The returned function releases the timeout of the parse.
+func (p *Parser) begin(ctx context.Context) context.CancelFunc {
+	cancel := func() {}
+	if p.options.Timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, p.options.Timeout)
+	}
+	p.ctx = ctx
+	p.depth = 0
+	p.checks = 0
+	p.aborted = false
+	p.tokensCut = false
+	p.diagnostics = nil
+	return cancel
+}
+
+// lex creates the tokens of the stream. The lexer stops at
+// Options.MaxTokens, the parser then stops at the end of file that follows
+// the last token, and when the context is done: the parser is aborted then
+// and only sees the end of file.
+func (p *Parser) lex() {
+	err := p.stream.CreateTokensContext(p.ctx, p.options.MaxTokens)
+	if err == lexer.ErrMaxTokens {
+		p.tokensCut = true
+	} else if err != nil {
+		p.aborted = true
+		eof := p.stream.Tokens[p.stream.EofPos]
+		p.diagnostics = append(p.diagnostics, lexer.Diagnostic{Kind: DiagnosticParseCanceled, Start: eof.Start, Message: "Parse canceled: " + err.Error()})
+	}
+}
+
+// start resets the state of the parser for a parse of the current stream.
+func (p *Parser) start() {
+	// the source may have been transcoded to UTF-8
+	p.source = p.stream.Content()
+	p.tree = nil
+	p.listElements = nil
+	p.renamedTokens = nil
+	p.blockIndents = p.blockIndents[:0]
+	p.unclosedBlock = p.options.IndentRecovery && hasUnclosedBlock(p.stream.Tokens)
+	p.reset()
+}
+
+// abort stops the parse: from now on the parser only sees the end of file,
+// so every node being parsed is closed with missing tokens.
+func (p *Parser) abort(kind lexer.DiagnosticKind, message string) {
+	if p.aborted {
+		return
+	}
+	p.aborted = true
+	p.diagnostics = append(p.diagnostics, lexer.Diagnostic{Kind: kind, Start: p.token.Start, Message: message})
+	p.stream.Pos = p.stream.EofPos
+	p.token = p.stream.ScanNext()
+}
+
+func (p *Parser) checkLimits() {
+	if p.aborted {
+		return
+	}
+	if p.options.MaxTokens > 0 {
+		// the index of the current token, the stream doesn't move past the
+		// end of file
+		index := p.stream.Pos - 1
+		if p.token.Kind == lexer.EndOfFileToken {
+			index = p.stream.EofPos
+		}
+		if index >= p.options.MaxTokens || p.tokensCut && index == p.stream.EofPos {
+			p.abort(DiagnosticMaxTokensExceeded, "Maximum number of tokens exceeded")
+			return
+		}
+	}
+	// a counter and not the position of the stream, which can skip the
+	// multiples of checkContextEvery
+	p.checks++
+	if p.ctx != nil && p.ctx.Done() != nil && p.checks%checkContextEvery == 0 {
+		select {
+		case <-p.ctx.Done():
+			p.abort(DiagnosticParseCanceled, "Parse canceled: "+p.ctx.Err().Error())
+		default:
+		}
+	}
+}
+
+// enter counts a nested statement or operand, leave must be called when it
+// is parsed. Every nested expression is parsed as an operand, so the binary
+// expressions aren't counted again.
+func (p *Parser) enter() {
+	p.depth++
+	maxDepth := p.options.MaxDepth
+	if maxDepth == 0 {
+		maxDepth = DefaultMaxDepth
+	}
+	if p.depth > maxDepth {
+		p.abort(DiagnosticMaxDepthExceeded, "Maximum nesting depth exceeded")
+	}
+}
+
+func (p *Parser) leave() {
+	p.depth--
+}
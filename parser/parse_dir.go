@@ -0,0 +1,135 @@
+package parser
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+
+	"github.com/emilioastarita/gphp/ast"
+	"github.com/emilioastarita/gphp/lexer"
+)
+
+// DirOptions configures ParseDir.
+type DirOptions struct {
+	Options
+	// Include are the globs of the files to parse, *.php by default.
+	// Globs are matched against the path relative to the root, with
+	// slashes, and against the file name.
+	Include []string
+	// Exclude are the globs of the files and directories to skip.
+	Exclude []string
+	// IncludeVendor parses the vendor directories, skipped by default.
+	IncludeVendor bool
+	// Workers is the number of files parsed at the same time, GOMAXPROCS
+	// by default.
+	Workers int
+}
+
+// DirResult is a parsed file of ParseDir. Err is set when the file could
+// not be read, the directory walked or when ctx was done before the file
+// was parsed, SourceFile is nil then.
+type DirResult struct {
+	Path        string
+	SourceFile  *ast.SourceFileNode
+	Diagnostics []lexer.Diagnostic
+	Err         error
+}
+
+// ParseDir walks root and parses the matching files on a pool of workers.
+// The results are sent in no particular order on the returned channel,
+// which is closed when all the files are parsed or ctx is done.
+func ParseDir(ctx context.Context, root string, opts DirOptions) <-chan DirResult {
+	include := opts.Include
+	if len(include) == 0 {
+		include = []string{"*.php"}
+	}
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	paths := make(chan string)
+	results := make(chan DirResult)
+
+	go func() {
+		defer close(paths)
+		filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				select {
+				case results <- DirResult{Path: path, Err: err}:
+				case <-ctx.Done():
+				}
+				return ctx.Err()
+			}
+			rel, _ := filepath.Rel(root, path)
+			rel = filepath.ToSlash(rel)
+			if info.IsDir() {
+				if path != root && (!opts.IncludeVendor && info.Name() == "vendor" || matchesGlob(opts.Exclude, rel)) {
+					return filepath.SkipDir
+				}
+				return ctx.Err()
+			}
+			if !matchesGlob(include, rel) || matchesGlob(opts.Exclude, rel) {
+				return nil
+			}
+			select {
+			case paths <- path:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		})
+	}()
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			p := New(opts.Options)
+			for path := range paths {
+				if ctx.Err() != nil {
+					continue
+				}
+				result := DirResult{Path: path}
+				source, err := ioutil.ReadFile(path)
+				if err != nil {
+					result.Err = err
+				} else {
+					result.SourceFile = p.ParseSourceFileContext(ctx, source, path)
+					result.Diagnostics = p.Diagnostics()
+					p.Reset()
+				}
+				// a tree cut short by the cancellation is not a result
+				if err := ctx.Err(); err != nil {
+					result = DirResult{Path: path, Err: err}
+				}
+				select {
+				case results <- result:
+				case <-ctx.Done():
+				}
+			}
+		}()
+	}
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+	return results
+}
+
+func matchesGlob(globs []string, rel string) bool {
+	name := filepath.Base(rel)
+	for _, glob := range globs {
+		if ok, _ := filepath.Match(glob, rel); ok {
+			return true
+		}
+		if ok, _ := filepath.Match(glob, name); ok {
+			return true
+		}
+	}
+	return false
+}
@@ -1,22 +1,92 @@
 package parser
 
 import (
+	"context"
+	"time"
+
 	"github.com/emilioastarita/gphp/ast"
 	"github.com/emilioastarita/gphp/lexer"
 )
 
 type Parser struct {
+	// Trace, when set, is called with the context changes and the recovery
+	// decisions of the parse, see TraceEvent.
+	Trace                             func(TraceEvent)
+	options                           Options
+	source                            []byte
 	stream                            *lexer.TokensStream
 	token                             *lexer.Token
 	currentParseContext               ParseContext
 	isParsingObjectCreationExpression bool
-	reservedWordTokens                []lexer.TokenKind
-	nameOrKeywordOrReservedWordTokens []lexer.TokenKind
-	nameOrReservedWordTokens          []lexer.TokenKind
-	parameterTypeDeclarationTokens    []lexer.TokenKind
-	returnTypeDeclarationTokens       []lexer.TokenKind
-	nameOrStaticOrReservedWordTokens  []lexer.TokenKind
-}
+	// indentation of the elements of the open blocks, only looked at when
+	// a brace or a paren of the source isn't closed
+	blockIndents  []int
+	unclosedBlock bool
+	// the last tree and its list elements, kept for Reparse
+	tree          *ast.SourceFileNode
+	listElements  []listElement
+	renamedTokens map[*lexer.Token]lexer.TokenKind
+	reuse         *reuse
+	// limits of the current parse
+	ctx         context.Context
+	depth       int
+	checks      int
+	aborted     bool
+	tokensCut   bool
+	diagnostics []lexer.Diagnostic
+}
+
+// Options are the settings of a parser. They never change while parsing,
+// the same Options can configure the parsers of many goroutines.
+type Options struct {
+	// Lexer are passed to the lexer, e.g. to enable short open tags.
+	Lexer lexer.Options
+	// MaxDepth is the maximum nesting of statements and expressions,
+	// DefaultMaxDepth when 0. A statement, a block and an operand are one
+	// level each, binary operators add none: `((1));` nests 4 levels.
+	MaxDepth int
+	// MaxTokens is the maximum number of tokens parsed, the end of file
+	// included, no limit when 0. The source is lexed only up to the limit.
+	MaxTokens int
+	// Timeout is the maximum duration of a parse, lexing included, no
+	// limit when 0.
+	Timeout time.Duration
+	// IndentRecovery keeps the errors of unclosed braces and parentheses
+	// local using the indentation of the code, see recovery.go. mstpp
+	// doesn't, so the trees of code with those errors differ from its
+	// trees.
+	IndentRecovery bool
+}
+
+// New returns a parser configured with opts. A Parser holds the state of
+// one parse and must not be used by several goroutines at once, use one
+// parser per goroutine or a Pool.
+func New(opts Options) *Parser {
+	return &Parser{options: opts}
+}
+
+// Reset drops the state of the last parse so that p does not keep its
+// tree alive, e.g. before putting it in a pool. Reparse parses from
+// scratch after a Reset.
+func (p *Parser) Reset() {
+	*p = Parser{Trace: p.Trace, options: p.options}
+}
+
+// token kind lists built once, they are only read while parsing.
+var typeDeclarationTokens = []lexer.TokenKind{lexer.ArrayKeyword, lexer.CallableKeyword, lexer.BoolReservedWord,
+	lexer.FloatReservedWord, lexer.IntReservedWord, lexer.StringReservedWord,
+	lexer.ObjectReservedWord}
+var returnTypeDeclarationTokens = append([]lexer.TokenKind{lexer.VoidReservedWord}, typeDeclarationTokens...)
+var parameterTypeDeclarationTokens = typeDeclarationTokens
+var reservedWordTokens = lexer.GetReservedWords()
+var nameOrStaticOrReservedWordTokens = append([]lexer.TokenKind{lexer.Name, lexer.StaticKeyword}, reservedWordTokens...)
+var nameOrKeywordOrReservedWordTokens = lexer.GetNameOrKeywordOrReservedWordTokens()
+var nameOrReservedWordTokens = lexer.GetNameOrReservedWordTokens()
+
+// token kind sets for the membership tests of the hot paths.
+var parameterTypeDeclarationSet = lexer.NewTokenKindSet(parameterTypeDeclarationTokens...)
+var nameOrStaticOrReservedWordSet = lexer.NewTokenKindSet(nameOrStaticOrReservedWordTokens...)
+var nameOrReservedWordSet = lexer.NewTokenKindSet(nameOrReservedWordTokens...)
 
 type ParseContext uint
 
@@ -37,26 +107,27 @@ const (
 )
 
 func (p *Parser) ParseSourceFile(source []byte, uri string) *ast.SourceFileNode {
+	return p.ParseSourceFileContext(context.Background(), source, uri)
+}
 
-	typeDeclaration := []lexer.TokenKind{lexer.ArrayKeyword, lexer.CallableKeyword, lexer.BoolReservedWord,
-		lexer.FloatReservedWord, lexer.IntReservedWord, lexer.StringReservedWord,
-		lexer.ObjectReservedWord}
-
-	p.returnTypeDeclarationTokens = []lexer.TokenKind{lexer.VoidReservedWord}
-	p.returnTypeDeclarationTokens = append(p.returnTypeDeclarationTokens, typeDeclaration...)
-
-	p.reservedWordTokens = lexer.GetReservedWords()
-	p.nameOrStaticOrReservedWordTokens = []lexer.TokenKind{lexer.Name, lexer.StaticKeyword}
-	p.nameOrStaticOrReservedWordTokens = append(p.nameOrStaticOrReservedWordTokens, p.reservedWordTokens...)
+// ParseSourceFileContext is like ParseSourceFile but stops when ctx is
+// done. When the parse is stopped, by ctx or by the limits of the options,
+// the returned tree is partial: the code left is missing from it and a
+// diagnostic tells why.
+func (p *Parser) ParseSourceFileContext(ctx context.Context, source []byte, uri string) *ast.SourceFileNode {
 
-	p.parameterTypeDeclarationTokens = typeDeclaration
-	p.nameOrKeywordOrReservedWordTokens = lexer.GetNameOrKeywordOrReservedWordTokens()
-	p.nameOrReservedWordTokens = lexer.GetNameOrReservedWordTokens()
-	p.stream = &lexer.TokensStream{}
+	defer p.begin(ctx)()
+	p.stream = &lexer.TokensStream{Options: p.options.Lexer}
 	p.stream.Source(source)
-	p.stream.CreateTokens()
-	p.reset()
-	sourceFile := &ast.SourceFileNode{P: nil, FileContents: source, Uri: uri}
+	p.lex()
+	p.reuse = nil
+	return p.parse(uri)
+}
+
+// parse parses the stream, begin must have been called.
+func (p *Parser) parse(uri string) *ast.SourceFileNode {
+	p.start()
+	sourceFile := &ast.SourceFileNode{P: nil, FileContents: p.source, Uri: uri}
 	sourceFile.StatementList = make([]ast.Node, 0)
 	if p.token.Kind != lexer.EndOfFileToken {
 		sourceFile.Add(p.parseInlineHtml(sourceFile))
@@ -64,28 +135,75 @@ func (p *Parser) ParseSourceFile(source []byte, uri string) *ast.SourceFileNode
 	list := p.parseList(sourceFile, SourceElements)
 	sourceFile.Merge(list)
 	sourceFile.EndOfFileToken = p.eat1(lexer.EndOfFileToken)
+	p.tree = sourceFile
+	if p.aborted {
+		// the elements open when the parse stopped end at the end of file
+		p.listElements = nil
+	}
 	return sourceFile
 }
 
+// Diagnostics returns the diagnostics of the last parse.
+func (p *Parser) Diagnostics() []lexer.Diagnostic {
+	if p.stream == nil {
+		return nil
+	}
+	if len(p.diagnostics) == 0 {
+		return p.stream.Diagnostics
+	}
+	return append(append([]lexer.Diagnostic(nil), p.stream.Diagnostics...), p.diagnostics...)
+}
+
+// Offsets maps the offsets of the tokens of the last parse, which are those
+// of its FileContents, back to the source passed to the parser. It is nil
+// when the source wasn't transcoded, then the offsets are the same, and
+// after a Reparse.
+func (p *Parser) Offsets() *lexer.OffsetMap {
+	if p.stream == nil {
+		return nil
+	}
+	return p.stream.Offsets
+}
+
 func (p *Parser) reset() {
 	p.advanceToken()
 	p.currentParseContext = 0
 }
 
 func (p *Parser) advanceToken() {
+	if p.aborted {
+		p.stream.Pos = p.stream.EofPos
+	}
 	c := p.stream.ScanNext()
 	p.token = c
+	p.checkLimits()
 }
 
 func (p *Parser) parseInlineHtml(source ast.Node) ast.Node {
+	prependedText := p.eatOptional1(lexer.ScriptSectionPrependedText)
 	end := p.eatOptional1(lexer.ScriptSectionEndTag)
 	text := p.eatOptional1(lexer.InlineHtml)
 	start := p.eatOptional1(lexer.ScriptSectionStartTag)
 	n := &ast.InlineHtml{}
 	n.P = source
+	n.ScriptSectionPrependedText = prependedText
 	n.ScriptSectionEndTag = end
 	n.ScriptSectionStartTag = start
 	n.Text = text
+	if start != nil && start.Text(p.source) == "<?=" {
+		// <?= expr; is the same as <?php echo expr;
+		echoStatement := &ast.ExpressionStatement{}
+		echoStatement.P = n
+		echoExpression := &ast.EchoExpression{}
+		echoExpression.P = echoStatement
+		echoExpression.Expressions = p.parseExpressionList(echoExpression)
+		if echoExpression.Expressions == nil {
+			echoExpression.Expressions = p.newMissingToken(lexer.Expression, p.token.FullStart, echoExpression)
+		}
+		echoStatement.Expression = []ast.Node{echoExpression}
+		echoStatement.Semicolon = p.eatSemicolonOrAbortStatement()
+		n.EchoStatement = echoStatement
+	}
 	return n
 }
 
@@ -112,11 +230,31 @@ func (p *Parser) eatOptional(kinds ...lexer.TokenKind) *lexer.Token {
 func (p *Parser) parseList(parentNode ast.Node, listParseContext ParseContext) []ast.Node {
 	savedParseContext := p.currentParseContext
 	p.currentParseContext |= 1 << listParseContext
+	p.trace(TraceEvent{Kind: TracePush, Context: listParseContext})
 	parseListElementFn := p.getParseListElementFn(listParseContext)
+	isBlock := p.options.IndentRecovery && isBraceList(listParseContext)
+	if isBlock {
+		p.blockIndents = append(p.blockIndents, unknownIndent)
+	}
 	nodes := make([]ast.Node, 0)
-	for p.isListTerminator(listParseContext) == false {
+	for p.isListTerminator(listParseContext) == false && !p.closesOpenBlock() {
+		if isBlock && p.blockIndent() == unknownIndent {
+			p.blockIndents[len(p.blockIndents)-1], _ = p.lineIndent(p.token)
+		}
 		if p.isValidListElement(listParseContext, p.token) {
-			element := parseListElementFn(parentNode)
+			var element ast.Node
+			if p.reuse != nil {
+				element = p.reuseListElement(listParseContext)
+			}
+			if element == nil {
+				first := p.tokenIndex()
+				objectCreationExpr := p.isParsingObjectCreationExpression
+				blockIndent := p.blockIndent()
+				element = parseListElementFn(parentNode)
+				if last := p.tokenIndex() - 1; last >= first {
+					p.listElements = append(p.listElements, listElement{element, listParseContext, p.currentParseContext, objectCreationExpr, blockIndent, first, last})
+				}
+			}
 			element.SetParent(parentNode)
 			nodes = append(nodes, element)
 			continue
@@ -124,10 +262,14 @@ func (p *Parser) parseList(parentNode ast.Node, listParseContext ParseContext) [
 		if p.isCurrentTokenValidInEnclosingContexts() {
 			break
 		}
-		nodes = append(nodes, ast.NewSkippedNode(p.token))
+		nodes = append(nodes, p.newSkippedNode(p.token))
 		p.advanceToken()
 	}
+	if isBlock {
+		p.blockIndents = p.blockIndents[:len(p.blockIndents)-1]
+	}
 	p.currentParseContext = savedParseContext
+	p.trace(TraceEvent{Kind: TracePop, Context: listParseContext})
 	return nodes
 }
 
@@ -174,6 +316,8 @@ func (p *Parser) parseModifiers() []*lexer.Token {
 }
 
 func (p *Parser) parseUnaryExpressionOrHigher(parentNode ast.Node) ast.Node {
+	p.enter()
+	defer p.leave()
 	token := p.token
 	switch token.Kind {
 	case lexer.PlusToken,
@@ -329,7 +473,7 @@ func (p *Parser) parseSimpleVariableFn() func(ast.Node) ast.Node {
 			tokNode.Token = tokName
 			variable.Name = tokNode
 		} else {
-			variable.Name = ast.NewMissingToken(lexer.VariableName, token.FullStart, nil)
+			variable.Name = p.newMissingToken(lexer.VariableName, token.FullStart, nil)
 		}
 
 		return variable
@@ -475,6 +619,8 @@ func (p *Parser) getParseListElementFn(context ParseContext) func(ast.Node) ast.
 
 func (p *Parser) parseStatementFn() func(ast.Node) ast.Node {
 	return func(parentNode ast.Node) ast.Node {
+		p.enter()
+		defer p.leave()
 		token := p.token
 		switch token.Kind {
 		// compound-statement
@@ -526,7 +672,7 @@ func (p *Parser) parseStatementFn() func(ast.Node) ast.Node {
 			// function-declaration
 		case lexer.FunctionKeyword:
 			// Check that this is not an anonymous-function-creation-expression
-			if p.lookahead(p.nameOrKeywordOrReservedWordTokens) || p.lookahead(lexer.AmpersandToken, p.nameOrKeywordOrReservedWordTokens) {
+			if p.lookahead(nameOrKeywordOrReservedWordTokens) || p.lookahead(lexer.AmpersandToken, nameOrKeywordOrReservedWordTokens) {
 				return p.parseFunctionDeclaration(parentNode)
 			}
 			break
@@ -536,7 +682,7 @@ func (p *Parser) parseStatementFn() func(ast.Node) ast.Node {
 			lexer.AbstractKeyword:
 			if !p.lookahead(lexer.ClassKeyword) {
 				p.advanceToken()
-				return ast.NewSkippedNode(token)
+				return p.newSkippedNode(token)
 			}
 			return p.parseClassDeclaration(parentNode)
 		case lexer.ClassKeyword:
@@ -590,7 +736,10 @@ func (p *Parser) parseStatementFn() func(ast.Node) ast.Node {
 		_, isMissing := ret.(*ast.Missing)
 
 		expressionStatement.Expression = []ast.Node{ret}
-		if isMissing {
+		if isMissing && p.token.Kind != lexer.EndOfFileToken {
+			// like mstpp, the token that can't start an expression is
+			// skipped after the missing expression
+			expressionStatement.Expression = append(expressionStatement.Expression, p.newSkippedNode(p.token))
 			p.advanceToken()
 		}
 
@@ -682,6 +831,7 @@ func (p *Parser) lookahead(expectedKinds ...interface{}) bool {
 	}
 	p.stream.Pos = startPos
 	p.token = startToken
+	p.trace(TraceEvent{Kind: TraceLookahead, Token: startToken, Expected: expectedKinds, Matched: succeeded})
 	return succeeded
 }
 
@@ -692,13 +842,14 @@ func (p *Parser) eat1(kind lexer.TokenKind) *lexer.Token {
 		return token
 	}
 	t := &lexer.Token{Kind: kind, FullStart: token.FullStart, Start: token.FullStart, Cat: lexer.TokenCatMissing}
+	p.trace(TraceEvent{Kind: TraceMissing, Token: t})
 	return t
 }
 
 func (p *Parser) parseExpression(parentNode ast.Node, force bool) ast.Node {
 	token := p.token
 	if token.Kind == lexer.EndOfFileToken {
-		return ast.NewMissingToken(lexer.Expression, token.FullStart, parentNode)
+		return p.newMissingToken(lexer.Expression, token.FullStart, parentNode)
 	}
 	fnExpression := p.parseExpressionFn()
 
@@ -729,6 +880,7 @@ func (p *Parser) eat(kinds ...lexer.TokenKind) *lexer.Token {
 		}
 	}
 	t := &lexer.Token{Kind: kinds[0], FullStart: token.FullStart, Start: token.FullStart, Cat: lexer.TokenCatMissing}
+	p.trace(TraceEvent{Kind: TraceMissing, Token: t})
 	return t
 }
 
@@ -788,7 +940,7 @@ func (p *Parser) parsePostfixExpressionRest(expression ast.Node, allowUpdateExpr
 
 	for {
 		tokenKind = p.token.Kind
-		if tokenKind == lexer.OpenBraceToken ||
+		if tokenKind == lexer.OpenBraceToken && !(p.options.IndentRecovery && p.isBlockOpenBrace()) ||
 			tokenKind == lexer.OpenBracketToken {
 			expression = p.parseSubscriptExpression(expression)
 			return p.parsePostfixExpressionRest(expression, true)
@@ -922,7 +1074,7 @@ func (p *Parser) parsePrimaryExpression(parentNode ast.Node) ast.Node {
 	if lexer.IsReservedWordToken(token.Kind) {
 		return p.parseQualifiedName(parentNode)
 	}
-	return ast.NewMissingToken(lexer.Expression, token.FullStart, parentNode)
+	return p.newMissingToken(lexer.Expression, token.FullStart, parentNode)
 }
 
 func (p *Parser) parseSimpleVariable(variable ast.Node) ast.Node {
@@ -986,22 +1138,22 @@ func (p *Parser) parseFunctionType(functionDeclaration ast.FunctionInterface, ca
 	functionDeclaration.SetByRefToken(p.eatOptional1(lexer.AmpersandToken))
 
 	if isAnonymous {
-		t := &ast.TokenNode{Token: p.eatOptional(p.nameOrKeywordOrReservedWordTokens...)}
+		t := &ast.TokenNode{Token: p.eatOptional(nameOrKeywordOrReservedWordTokens...)}
 		functionDeclaration.SetName(t)
 	} else {
-		t := &ast.TokenNode{Token: p.eat(p.nameOrKeywordOrReservedWordTokens...)}
+		t := &ast.TokenNode{Token: p.eat(nameOrKeywordOrReservedWordTokens...)}
 		functionDeclaration.SetName(t)
 	}
 
 	hasNameToken := functionDeclaration.GetName() != nil && functionDeclaration.GetName().GetToken() != nil
 
 	if hasNameToken {
-		functionDeclaration.GetName().GetToken().Kind = lexer.Name
+		p.renameToken(functionDeclaration.GetName().GetToken(), lexer.Name)
 	}
 
 	if isAnonymous && hasNameToken {
 		// Anonymous functions should not have names
-		functionDeclaration.SetName(ast.NewSkippedNode(functionDeclaration.GetName().GetToken())) // TODO instaed handle this during post-walk
+		functionDeclaration.SetName(p.newSkippedNode(functionDeclaration.GetName().GetToken())) // TODO instaed handle this during post-walk
 	}
 
 	functionDeclaration.SetOpenParen(p.eat1(lexer.OpenParenToken))
@@ -1080,7 +1232,7 @@ func (p *Parser) parseDelimitedList(node ast.DelimitedList, delimiter lexer.Toke
 	}
 
 	node.SetParent(parentNode)
-	if node.Children() == nil {
+	if node.Elements() == nil {
 		return nil
 	}
 	return node
@@ -1566,8 +1718,8 @@ func (p *Parser) parseClassDeclaration(parentNode ast.Node) ast.Node {
 	classNode.P = parentNode
 	classNode.AbstractOrFinalModifier = p.eatOptional(lexer.AbstractKeyword, lexer.FinalKeyword)
 	classNode.ClassKeyword = p.eat1(lexer.ClassKeyword)
-	classNode.Name = p.eat(p.nameOrReservedWordTokens...) // TODO should be any
-	classNode.Name.Kind = lexer.Name
+	classNode.Name = p.eat(nameOrReservedWordTokens...) // TODO should be any
+	p.renameToken(classNode.Name, lexer.Name)
 	classNode.ClassBaseClause = p.parseClassBaseClause(classNode)
 	classNode.ClassInterfaceClause = p.parseClassInterfaceClause(classNode)
 	classNode.ClassMembers = p.parseClassMembers(classNode)
@@ -1747,7 +1899,7 @@ func (p *Parser) parseSubscriptExpression(expression ast.Node) ast.Node {
 func (p *Parser) parseNumericLiteralExpression(parentNode ast.Node) ast.Node {
 	numericLiteral := &ast.NumericLiteral{}
 	numericLiteral.P = parentNode
-	numericLiteral.Children = p.token
+	numericLiteral.Child = p.token
 	p.advanceToken()
 	return numericLiteral
 }
@@ -1756,7 +1908,7 @@ func (p *Parser) parseStringLiteralExpression(parentNode ast.Node) ast.Node {
 	// TODO validate input token
 	expression := &ast.StringLiteral{}
 	expression.P = parentNode
-	expression.Children = p.token
+	expression.Child = p.token
 	p.advanceToken()
 	return expression
 }
@@ -1807,7 +1959,7 @@ func (p *Parser) parseStringLiteralExpression2(parentNode ast.Node) ast.Node {
 			lexer.EndOfFileToken,
 			lexer.HeredocEnd:
 			expression.EndQuote = p.eat(expression.StartQuote.Kind, lexer.HeredocEnd)
-			expression.Children = children
+			expression.Child = children
 			return expression
 		case lexer.VariableName:
 			children = append(children, p.parseTemplateStringExpression(expression))
@@ -1915,7 +2067,7 @@ func (p *Parser) parseExitIntrinsicExpression(parentNode ast.Node) ast.Node {
 	exitExpression.ExitOrDieKeyword = p.eat(lexer.ExitKeyword, lexer.DieKeyword)
 	if exitExpression.ExitOrDieKeyword != nil {
 		// normalize always to ExitKeyWord
-		exitExpression.ExitOrDieKeyword.Kind = lexer.ExitKeyword
+		p.renameToken(exitExpression.ExitOrDieKeyword, lexer.ExitKeyword)
 	}
 	exitExpression.OpenParen = p.eatOptional1(lexer.OpenParenToken)
 	if exitExpression.OpenParen != nil {
@@ -1954,7 +2106,7 @@ func (p *Parser) parsePrintIntrinsicExpression(parentNode ast.Node) ast.Node {
 func (p *Parser) parseReservedWordExpression(parentNode ast.Node) ast.Node {
 	reservedWord := &ast.ReservedWord{}
 	reservedWord.P = parentNode
-	reservedWord.Children = p.token
+	reservedWord.Child = p.token
 	p.advanceToken()
 	return reservedWord
 }
@@ -2257,7 +2409,7 @@ func (p *Parser) parseTemplateStringSubscriptExpression(postfixExpression ast.No
 	} else if token.Kind == lexer.Name {
 		subscriptExpression.AccessExpression = p.parseTemplateStringSubscriptStringLiteral(subscriptExpression)
 	} else {
-		subscriptExpression.AccessExpression = ast.NewMissingToken(lexer.Expression, token.FullStart, nil)
+		subscriptExpression.AccessExpression = p.newMissingToken(lexer.Expression, token.FullStart, nil)
 	}
 	subscriptExpression.CloseBracketOrBrace = p.eat1(lexer.CloseBracketToken)
 	return subscriptExpression
@@ -2266,7 +2418,7 @@ func (p *Parser) parseTemplateStringSubscriptExpression(postfixExpression ast.No
 func (p *Parser) parseTemplateStringSubscriptStringLiteral(parentNode *ast.SubscriptExpression) ast.Node {
 	expression := &ast.StringLiteral{}
 	expression.P = parentNode
-	expression.Children = p.eat1(lexer.Name)
+	expression.Child = p.eat1(lexer.Name)
 	return expression
 }
 
@@ -2300,13 +2452,13 @@ func (p *Parser) parseMemberName(parentNode ast.Node) ast.Node {
 	default:
 		if lexer.IsNameOrKeywordOrReservedWordTokens(token.Kind) {
 			p.advanceToken()
-			token.Kind = lexer.Name
+			p.renameToken(token, lexer.Name)
 			tokNode := &ast.TokenNode{}
 			tokNode.Token = token
 			return tokNode
 		}
 	}
-	return ast.NewMissingToken(lexer.MemberName, p.token.FullStart, nil)
+	return p.newMissingToken(lexer.MemberName, p.token.FullStart, nil)
 }
 
 func (p *Parser) parseConstElementFn() ParseElementFn {
@@ -2315,7 +2467,7 @@ func (p *Parser) parseConstElementFn() ParseElementFn {
 		constElement.P = parentNode
 		constElement.Name = p.token
 		p.advanceToken()
-		constElement.Name.Kind = lexer.Name // to support keyword names
+		p.renameToken(constElement.Name, lexer.Name) // to support keyword names
 		constElement.EqualsToken = p.eat1(lexer.EqualsToken)
 		// TODO add post-parse rule that checks for invalid assignments
 		constElement.Assignment = p.parseExpression(constElement, false)
@@ -2341,7 +2493,7 @@ func (p *Parser) isParameterStartFn() ElementStartFn {
 			return true
 		}
 		// scalar-type
-		return p.isTokenMember(token.Kind, p.parameterTypeDeclarationTokens)
+		return parameterTypeDeclarationSet.Contains(token.Kind)
 	}
 }
 
@@ -2365,18 +2517,9 @@ func (p *Parser) parseParameterFn() ParseElementFn {
 	}
 }
 
-func (p *Parser) isTokenMember(tok lexer.TokenKind, tokens []lexer.TokenKind) bool {
-	for _, t := range tokens {
-		if t == tok {
-			return true
-		}
-	}
-	return false
-}
-
 func (p *Parser) tryParseParameterTypeDeclaration(parentNode *ast.Parameter) ast.Node {
 	var parameterTypeDeclaration ast.Node
-	tn := &ast.TokenNode{Token: p.eatOptional(p.parameterTypeDeclarationTokens...)}
+	tn := &ast.TokenNode{Token: p.eatOptional(parameterTypeDeclarationTokens...)}
 	parameterTypeDeclaration = tn
 	if tn.Token == nil {
 		parameterTypeDeclaration = p.parseQualifiedName(parentNode)
@@ -2415,14 +2558,14 @@ func (p *Parser) parseAnonymousFunctionUseClause(parentNode *ast.AnonymousFuncti
 }
 
 func (p *Parser) parseReturnTypeDeclaration(parentNode ast.FunctionInterface) ast.Node {
-	tokNode := &ast.TokenNode{Token: p.eatOptional(p.returnTypeDeclarationTokens...)}
+	tokNode := &ast.TokenNode{Token: p.eatOptional(returnTypeDeclarationTokens...)}
 	var returnTypeDeclaration ast.Node = tokNode
 	if tokNode.Token == nil {
 		returnTypeDeclaration = p.parseQualifiedName(parentNode)
 	}
 
 	if returnTypeDeclaration == nil {
-		returnTypeDeclaration = ast.NewMissingToken(lexer.ReturnType, p.token.FullStart, nil)
+		returnTypeDeclaration = p.newMissingToken(lexer.ReturnType, p.token.FullStart, nil)
 	}
 	return returnTypeDeclaration
 }
@@ -2449,18 +2592,18 @@ func (p *Parser) parseQualifiedNameFn() func(parentNode ast.Node) ast.Node {
 				// TODO more tests
 
 				if p.lookahead(lexer.BackslashToken) {
-					return p.isTokenMember(token.Kind, p.nameOrReservedWordTokens)
+					return nameOrReservedWordSet.Contains(token.Kind)
 				}
-				return p.isTokenMember(token.Kind, p.nameOrStaticOrReservedWordTokens)
+				return nameOrStaticOrReservedWordSet.Contains(token.Kind)
 			},
 			func(parentNode ast.Node) ast.Node {
 				var name *lexer.Token
 				if p.lookahead(lexer.BackslashToken) {
-					name = p.eat(p.nameOrReservedWordTokens...)
+					name = p.eat(nameOrReservedWordTokens...)
 				} else {
-					name = p.eat(p.nameOrStaticOrReservedWordTokens...) // TODO support keyword name
+					name = p.eat(nameOrStaticOrReservedWordTokens...) // TODO support keyword name
 				}
-				name.Kind = lexer.Name // bool/true/null/static should not be treated as keywords in this case
+				p.renameToken(name, lexer.Name) // bool/true/null/static should not be treated as keywords in this case
 				return &ast.TokenNode{Token: name}
 			}, node, false)
 
@@ -2469,7 +2612,7 @@ func (p *Parser) parseQualifiedNameFn() func(parentNode ast.Node) ast.Node {
 		}
 
 		if nameParts != nil && nameParts.Len() != 0 {
-			node.NameParts = nameParts.Children()
+			node.NameParts = nameParts.Elements()
 		}
 		return node
 	}
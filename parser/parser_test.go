@@ -1,25 +1,152 @@
 package parser
 
 import (
+	"bytes"
+	"context"
 	"testing"
+	"time"
 	//"encoding/json"
 	"encoding/json"
+	"flag"
 	"fmt"
 	"github.com/emilioastarita/gphp/ast"
+	"github.com/emilioastarita/gphp/lexer"
 	diff "github.com/yudai/gojsondiff"
 	"github.com/yudai/gojsondiff/formatter"
 	"io/ioutil"
+	"math/rand"
+	"os"
 	path "path"
 	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
 )
 
+// BenchmarkComplex reads complex.php, a synthetic file written for the
+// benchmarks, not code from a real project.
+func BenchmarkComplex(b *testing.B) {
+	data, _ := ioutil.ReadFile("../lexer/cases/complex.php")
+	b.SetBytes(int64(len(data)))
+	b.ReportAllocs()
+
+	for n := 0; n < b.N; n++ {
+		p := Parser{}
+		p.ParseSourceFile(data, "")
+	}
+}
+
+// BenchmarkDebug reads debug/debug.php, the script generating the mstpp
+// goldens, as a real file written for use rather than for the benchmarks.
+func BenchmarkDebug(b *testing.B) {
+	data, _ := ioutil.ReadFile("../debug/debug.php")
+	b.SetBytes(int64(len(data)))
+	b.ReportAllocs()
+
+	for n := 0; n < b.N; n++ {
+		p := Parser{}
+		p.ParseSourceFile(data, "")
+	}
+}
+
+// projectSize is the size of the project of BenchmarkProject, about the
+// size of a mid-sized php project.
+const projectSize = 10 << 20
+
+// project returns the files of BenchmarkProject: copies of complex.php,
+// debug/debug.php and the nikic cases, from 0.5KB to 15KB each, until
+// projectSize bytes. The repository holds no bigger real-world code.
+func project() ([][]byte, int) {
+	names, _ := filepath.Glob("../nikic/cases/*.php")
+	names = append(names, "../lexer/cases/complex.php", "../debug/debug.php")
+	var sources, files [][]byte
+	for _, name := range names {
+		data, _ := ioutil.ReadFile(name)
+		sources = append(sources, data)
+	}
+	size := 0
+	for i := 0; size < projectSize; i++ {
+		data := sources[i%len(sources)]
+		files = append(files, data)
+		size += len(data)
+	}
+	return files, size
+}
+
+// BenchmarkProject parses every file of a project of projectSize bytes, the
+// throughput to compare with the targets of the README.
+func BenchmarkProject(b *testing.B) {
+	files, size := project()
+	b.SetBytes(int64(size))
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for n := 0; n < b.N; n++ {
+		for _, data := range files {
+			p := Parser{}
+			p.ParseSourceFile(data, "")
+		}
+	}
+}
+
+var targets = flag.Bool("targets", false, "check the throughput targets of the README")
+
+// TestTargets runs BenchmarkProject and fails under the parser throughput
+// target of the README. It only runs with -targets, the numbers depend on
+// the machine.
+func TestTargets(t *testing.T) {
+	if !*targets {
+		t.Skip("run with -targets")
+	}
+	const target = 5 << 20 // bytes per second
+	result := testing.Benchmark(BenchmarkProject)
+	throughput := float64(result.Bytes*int64(result.N)) / result.T.Seconds()
+	t.Logf("%.1f MB/s", throughput/(1<<20))
+	if throughput < target {
+		t.Errorf("parsing the project at %.1f MB/s, the target is %d MB/s", throughput/(1<<20), target>>20)
+	}
+}
+
+// BenchmarkSerialize serializes the tree of complex.php.
+func BenchmarkSerialize(b *testing.B) {
+	data, _ := ioutil.ReadFile("../lexer/cases/complex.php")
+	p := Parser{}
+	sourceFile := p.ParseSourceFile(data, "")
+	b.ReportAllocs()
+
+	for n := 0; n < b.N; n++ {
+		ast.Serialize(sourceFile)
+	}
+}
+
+// BenchmarkCorpus parses every file of the cases directory.
+func BenchmarkCorpus(b *testing.B) {
+	files, _ := filepath.Glob("cases/*.php")
+	var sources [][]byte
+	size := 0
+	for _, file := range files {
+		data, _ := ioutil.ReadFile(file)
+		sources = append(sources, data)
+		size += len(data)
+	}
+	b.SetBytes(int64(size))
+	b.ReportAllocs()
+
+	for n := 0; n < b.N; n++ {
+		for _, data := range sources {
+			p := Parser{}
+			p.ParseSourceFile(data, "")
+		}
+	}
+}
+
 func TestParser(t *testing.T) {
 	p := Parser{}
 	sourceFile := p.ParseSourceFile([]byte(`<?php
 $c[1]
 `), "")
 
-	jsonSource, err := json.Marshal(ast.Serialize(&sourceFile))
+	jsonSource, err := json.Marshal(ast.Serialize(sourceFile))
 	if err != nil {
 		fmt.Println(err)
 	} else {
@@ -30,11 +157,24 @@ $c[1]
 }
 
 func TestCases(t *testing.T) {
+	testCases(t, "cases", Options{})
+}
+
+// TestRecoveryCases checks the trees of Options.IndentRecovery, they aren't
+// mstpp trees so they don't live in cases.
+func TestRecoveryCases(t *testing.T) {
+	testCases(t, "recovery", Options{IndentRecovery: true})
+}
+
+func testCases(t *testing.T, dir string, opts Options) {
 	postfix := ".tree"
 	postfixLen := len(postfix)
 
 	skipFiles := SKIPPED_TESTS
-	resultFiles, _ := filepath.Glob("cases/*.php" + postfix)
+	resultFiles, _ := filepath.Glob(filepath.Join(dir, "*.php"+postfix))
+	if len(resultFiles) == 0 {
+		t.Fatalf("no cases found in %s", dir)
+	}
 
 	for _, resultFile := range resultFiles {
 
@@ -51,9 +191,9 @@ func TestCases(t *testing.T) {
 
 			sourceCase, _ := ioutil.ReadFile(sourceFileName)
 
-			p := Parser{}
+			p := New(opts)
 			sourceFile := p.ParseSourceFile(sourceCase, "")
-			jsonSource, _ := json.Marshal(ast.Serialize(&sourceFile))
+			jsonSource, _ := json.Marshal(ast.Serialize(sourceFile))
 
 			differ := diff.New()
 			d, err := differ.Compare(jsonSource, resultCase)
@@ -86,3 +226,457 @@ func TestCases(t *testing.T) {
 
 	}
 }
+
+func TestShortOpenTagEcho(t *testing.T) {
+	p := New(Options{Lexer: lexer.Options{ShortOpenTag: true}})
+	sourceFile := p.ParseSourceFile([]byte(`<h1><? $title = "hello" ?><?= $title, "!" ?></h1>`), "")
+
+	echoStatements := 0
+	for _, statement := range sourceFile.StatementList {
+		if inlineHtml, ok := statement.(*ast.InlineHtml); ok && inlineHtml.EchoStatement != nil {
+			echoExpression := inlineHtml.EchoStatement.Expression[0].(*ast.EchoExpression)
+			if echoExpression.Expressions.(ast.DelimitedList).Len() != 3 {
+				t.Errorf("expected two expressions and a comma in the echo tag")
+			}
+			echoStatements++
+		}
+	}
+	if echoStatements != 1 {
+		t.Errorf("expected one echo statement, got %d", echoStatements)
+	}
+}
+
+func TestPrependedText(t *testing.T) {
+	p := Parser{}
+	sourceFile := p.ParseSourceFile([]byte("#!/usr/bin/env php\n<?php\necho 1;\n"), "")
+
+	inlineHtml := sourceFile.StatementList[0].(*ast.InlineHtml)
+	if inlineHtml.ScriptSectionPrependedText == nil || inlineHtml.ScriptSectionStartTag == nil {
+		t.Errorf("expected the #! line and the start tag in the first inline html")
+	}
+	if len(sourceFile.StatementList) != 2 {
+		t.Errorf("expected 2 statements, got %d", len(sourceFile.StatementList))
+	}
+}
+
+// TestSkippedExpression checks the mstpp shape of a statement whose token
+// can't start an expression: the missing expression then the skipped token,
+// inside the expression statement.
+func TestSkippedExpression(t *testing.T) {
+	p := Parser{}
+	sourceFile := p.ParseSourceFile([]byte("<?php do '"), "")
+
+	doStatement := sourceFile.StatementList[1].(*ast.DoStatement)
+	expression := doStatement.Statement.(*ast.ExpressionStatement).Expression
+	if len(expression) != 2 {
+		t.Fatalf("expected a missing expression and a skipped token, got %d nodes", len(expression))
+	}
+	_, missing := expression[0].(*ast.Missing)
+	skipped, ok := expression[1].(*ast.SkippedNode)
+	if !missing || !ok || skipped.Token.Kind != lexer.EncapsedAndWhitespace {
+		t.Errorf("expected a missing expression and a skipped token, got %T and %T", expression[0], expression[1])
+	}
+}
+
+// TestTranscodedOffsets maps the tokens of a Latin-1 source, parsed as
+// UTF-8, back to the bytes of the source.
+func TestTranscodedOffsets(t *testing.T) {
+	source := []byte("<?php $caf\xE9 = '\xE0 \xE9'; $b;")
+	p := New(Options{Lexer: lexer.Options{Encoding: lexer.EncodingLatin1}})
+	sourceFile := p.ParseSourceFile(source, "")
+	if string(sourceFile.FileContents) == string(source) || p.Offsets() == nil {
+		t.Fatal("expected a transcoded source and its offsets")
+	}
+	variable := sourceFile.StatementList[2].(*ast.ExpressionStatement).Expression[0].(*ast.Variable)
+	token := variable.Name.(*ast.TokenNode).Token
+	start := p.Offsets().OriginalOffset(token.Start)
+	end := p.Offsets().OriginalOffset(token.FullStart + token.Length)
+	if text := string(source[start:end]); text != "$b" {
+		t.Errorf("expected $b at the mapped offsets, got %q", text)
+	}
+	assignment := sourceFile.StatementList[1].(*ast.ExpressionStatement).Expression[0].(*ast.AssignmentExpression)
+	token = assignment.LeftOperand.(*ast.Variable).Name.(*ast.TokenNode).Token
+	start = p.Offsets().OriginalOffset(token.Start)
+	end = p.Offsets().OriginalOffset(token.FullStart + token.Length)
+	if text := string(source[start:end]); text != "$caf\xE9" {
+		t.Errorf("expected $caf\\xE9 at the mapped offsets, got %q", text)
+	}
+
+	p = New(Options{})
+	p.ParseSourceFile(source, "")
+	if p.Offsets() != nil {
+		t.Error("expected no offsets for a source that isn't transcoded")
+	}
+}
+
+var editSnippets = []string{
+	"", " ", "\n", ";", "{", "}", "(", ")", "'", "\"", "/*", "*/", "//", "?>", "<?php ",
+	"$a", "$a = 1;", "function f() {", "class A {", "if ($a) {", "} else {", "else", "public ",
+	"static", "echo 1;", "return;", "new class {", "fn", "::", "->", "[", "]", ",", "=>",
+}
+
+// serialized returns the json of a tree, to compare two parses.
+func serialized(sourceFile *ast.SourceFileNode) []byte {
+	output, _ := json.Marshal(ast.Serialize(sourceFile))
+	return output
+}
+
+func TestReparse(t *testing.T) {
+	sourceFiles, _ := filepath.Glob("cases/*.php")
+	sourceFiles = append(sourceFiles, "../lexer/cases/complex.php")
+	testReparse(t, sourceFiles, Options{})
+}
+
+func TestReparseRecovery(t *testing.T) {
+	sourceFiles, _ := filepath.Glob("recovery/*.php")
+	sourceFiles = append(sourceFiles, "../lexer/cases/complex.php")
+	testReparse(t, sourceFiles, Options{IndentRecovery: true})
+
+	// the indentation of a reused block is read before its first token
+	source := []byte("<?php\n\nas{:\nfunction g")
+	edit := Edit{6, 1, []byte("    ")}
+	newSource := []byte("<?php\n    as{:\nfunction g")
+	p := New(Options{IndentRecovery: true})
+	sourceFile := p.Reparse(p.ParseSourceFile(source, ""), edit)
+	if expected := serialized(New(Options{IndentRecovery: true}).ParseSourceFile(newSource, "")); !bytes.Equal(serialized(sourceFile), expected) {
+		t.Errorf("indenting the line of a block differs from a full parse of:\n%s", newSource)
+	}
+}
+
+// TestRecoveryBalanced checks that IndentRecovery changes nothing, and
+// looks ahead no more, when every brace and paren is closed.
+func TestRecoveryBalanced(t *testing.T) {
+	source := []byte("<?php\nif ($a) {\n    $b = 1;\nfunction g() {}\n    class A {}\n}\n")
+	parse := func(opts Options) ([]byte, int) {
+		lookaheads := 0
+		p := New(opts)
+		p.Trace = func(event TraceEvent) {
+			if event.Kind == TraceLookahead {
+				lookaheads++
+			}
+		}
+		return serialized(p.ParseSourceFile(source, "")), lookaheads
+	}
+	tree, lookaheads := parse(Options{})
+	recovered, recoveryLookaheads := parse(Options{IndentRecovery: true})
+	if !bytes.Equal(tree, recovered) || lookaheads != recoveryLookaheads {
+		t.Errorf("expected the same tree and %d lookaheads, got %d", lookaheads, recoveryLookaheads)
+	}
+	for text, unclosed := range map[string]bool{"<?php ({})": false, "<?php } {}": false, "<?php {": true, "<?php (}": true, `<?php "{$a}"`: false, `<?php "{$a"`: true} {
+		stream := lexer.TokensStream{}
+		stream.Source([]byte(text))
+		stream.CreateTokens()
+		if hasUnclosedBlock(stream.Tokens) != unclosed {
+			t.Errorf("%s: expected unclosed %v", text, unclosed)
+		}
+	}
+}
+
+func testReparse(t *testing.T, sourceFiles []string, opts Options) {
+	random := rand.New(rand.NewSource(1))
+
+	for _, sourceFileName := range sourceFiles {
+		data, _ := ioutil.ReadFile(sourceFileName)
+		p := New(opts)
+		sourceFile := p.ParseSourceFile(data, "")
+		edits := 10
+		if len(data) > 1000 {
+			edits = 100
+		}
+		for i := 0; i < edits; i++ {
+			source := sourceFile.FileContents
+			start := random.Intn(len(source) + 1)
+			oldLength := random.Intn(len(source) - start + 1)
+			if oldLength > 10 {
+				oldLength = random.Intn(10)
+			}
+			edit := Edit{start, oldLength, []byte(editSnippets[random.Intn(len(editSnippets))])}
+			newSource := append(append(append([]byte{}, source[:start]...), edit.NewText...), source[start+oldLength:]...)
+
+			fresh := New(opts)
+			expected := serialized(fresh.ParseSourceFile(newSource, ""))
+			sourceFile = p.Reparse(sourceFile, edit)
+			if !bytes.Equal(serialized(sourceFile), expected) {
+				t.Errorf("%s: edit %d %+q at %d replacing %d bytes differs from a full parse of:\n%s",
+					sourceFileName, i, edit.NewText, start, oldLength, newSource)
+				break
+			}
+		}
+	}
+}
+
+func TestReparseReusesNodes(t *testing.T) {
+	data, _ := ioutil.ReadFile("../lexer/cases/complex.php")
+	p := Parser{}
+	sourceFile := p.ParseSourceFile(data, "")
+	first := sourceFile.StatementList[1]
+	last := sourceFile.StatementList[len(sourceFile.StatementList)-2]
+
+	// a statement added in the middle of the file keeps the ones around it
+	middle := bytes.Index(data, []byte("\nclass Router")) + 1
+	newSourceFile := p.Reparse(sourceFile, Edit{middle, 0, []byte("$x = 1;\n")})
+	if newSourceFile.StatementList[1] != first || newSourceFile.StatementList[len(newSourceFile.StatementList)-2] != last {
+		t.Errorf("statements around the edit were not reused")
+	}
+}
+
+func TestParseFragments(t *testing.T) {
+	p := Parser{}
+
+	expression, diagnostics := p.ParseExpression([]byte(`$a + 1`))
+	if _, ok := expression.(*ast.BinaryExpression); !ok || len(diagnostics) != 0 {
+		t.Errorf("expected a binary expression, got %T %v", expression, diagnostics)
+	}
+	_, diagnostics = p.ParseExpression([]byte(`$a + 1; $b`))
+	if len(diagnostics) != 1 || diagnostics[0].Kind != DiagnosticUnexpectedToken || diagnostics[0].Start != 6 || diagnostics[0].Length != 4 {
+		t.Errorf("expected the trailing tokens to be reported, got %v", diagnostics)
+	}
+
+	statements, diagnostics := p.ParseStatements([]byte("$a = 1; // comment\necho $a;\nclass A {}"))
+	if len(statements) != 3 || len(diagnostics) != 0 {
+		t.Errorf("expected 3 statements, got %d %v", len(statements), diagnostics)
+	}
+	statements, diagnostics = p.ParseStatements([]byte("echo 1; } echo 2;"))
+	if len(statements) != 1 || len(diagnostics) != 1 {
+		t.Errorf("expected the statements after } to be reported, got %d %v", len(statements), diagnostics)
+	}
+
+	members, diagnostics := p.ParseClassMembers([]byte("const A = 1; private $b; public function c() {}"))
+	if len(members) != 3 || len(diagnostics) != 0 {
+		t.Errorf("expected 3 members, got %d %v", len(members), diagnostics)
+	}
+	if _, ok := members[2].(*ast.MethodDeclaration); !ok {
+		t.Errorf("expected a method, got %T", members[2])
+	}
+
+	returnType, diagnostics := p.ParseType([]byte(`?\Foo\Bar`))
+	if _, ok := returnType.ReturnType.(*ast.QualifiedName); !ok || returnType.QuestionToken == nil || len(diagnostics) != 0 {
+		t.Errorf("expected a nullable qualified name, got %v %v", returnType, diagnostics)
+	}
+	returnType, _ = p.ParseType([]byte(`int`))
+	if tokenNode, ok := returnType.ReturnType.(*ast.TokenNode); !ok || tokenNode.Token.Kind != lexer.IntReservedWord {
+		t.Errorf("expected int, got %v", returnType.ReturnType)
+	}
+}
+
+// TestConcurrentParse parses the cases from many goroutines, run it with
+// go test -race to check parsers don't share state.
+func TestConcurrentParse(t *testing.T) {
+	sourceFiles, _ := filepath.Glob("cases/*.php")
+	sources := make([][]byte, len(sourceFiles))
+	expected := make([][]byte, len(sourceFiles))
+	for i, sourceFileName := range sourceFiles {
+		sources[i], _ = ioutil.ReadFile(sourceFileName)
+		expected[i] = serialized(New(Options{}).ParseSourceFile(sources[i], ""))
+	}
+
+	pool := NewPool(Options{})
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for n := range sources {
+				i := (n + g*len(sources)/8) % len(sources)
+				if !bytes.Equal(serialized(pool.ParseSourceFile(sources[i], "")), expected[i]) {
+					t.Errorf("%s: concurrent parse differs", sourceFiles[i])
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+}
+
+func TestPool(t *testing.T) {
+	pool := NewPool(Options{MaxTokens: 100})
+	p := pool.Get()
+	traced := false
+	p.Trace = func(TraceEvent) { traced = true }
+	p.ParseSourceFile([]byte("<?php }"), "")
+	pool.Put(p)
+	if p.Trace != nil || p.tree != nil || p.options.MaxTokens != 100 {
+		t.Errorf("expected Put to reset the parser but its options, got %+v", p)
+	}
+	if !traced {
+		t.Error("expected the parse traced before Put")
+	}
+}
+
+func TestParseDir(t *testing.T) {
+	root, _ := ioutil.TempDir("", "gphp")
+	defer os.RemoveAll(root)
+	files := map[string]string{
+		"a.php":         "<?php echo 1;",
+		"src/b.php":     "<?php /* unterminated",
+		"src/c.txt":     "not php",
+		"src/c.phtml":   "<?php echo 3;",
+		"vendor/d.php":  "<?php echo 4;",
+		"tests/e.php":   "<?php echo 5;",
+		"src/gen/f.php": "<?php echo 6;",
+		"src/gen_g.php": "<?php echo 7;",
+	}
+	for name, contents := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		os.MkdirAll(filepath.Dir(path), 0755)
+		ioutil.WriteFile(path, []byte(contents), 0644)
+	}
+
+	opts := DirOptions{Include: []string{"*.php", "*.phtml"}, Exclude: []string{"tests", "src/gen"}, Workers: 3}
+	parsed := map[string]int{}
+	for result := range ParseDir(context.Background(), root, opts) {
+		rel, _ := filepath.Rel(root, result.Path)
+		if result.Err != nil || result.SourceFile == nil {
+			t.Errorf("%s: %v", rel, result.Err)
+			continue
+		}
+		parsed[filepath.ToSlash(rel)] = len(result.Diagnostics)
+	}
+	expected := map[string]int{"a.php": 0, "src/b.php": 1, "src/c.phtml": 0, "src/gen_g.php": 0}
+	if !reflect.DeepEqual(parsed, expected) {
+		t.Errorf("got %v, want %v", parsed, expected)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	results := ParseDir(ctx, root, DirOptions{IncludeVendor: true})
+	timeout := time.After(10 * time.Second)
+	for done := false; !done; {
+		select {
+		case result, ok := <-results:
+			if !ok {
+				done = true
+			} else if result.Err != ctx.Err() || result.SourceFile != nil {
+				t.Errorf("%s: result after cancellation: %v, %v", result.Path, result.SourceFile, result.Err)
+			}
+		case <-timeout:
+			t.Fatal("the results channel was not closed after cancellation")
+		}
+	}
+}
+
+func TestParseLimits(t *testing.T) {
+	nested := "<?php $a = " + strings.Repeat("(", 200000) + "1" + strings.Repeat(")", 200000) + "; echo 2;"
+	cases := []struct {
+		name    string
+		options Options
+		source  string
+		kind    lexer.DiagnosticKind
+	}{
+		{"default depth", Options{}, nested, DiagnosticMaxDepthExceeded},
+		{"max depth", Options{MaxDepth: 20}, "<?php if (1) { if (2) { " + strings.Repeat("[", 30) + "1" + strings.Repeat("]", 30) + "; } }", DiagnosticMaxDepthExceeded},
+		{"nested statements", Options{MaxDepth: 20}, "<?php " + strings.Repeat("if (1) ", 30) + "echo 1;", DiagnosticMaxDepthExceeded},
+		{"max tokens", Options{MaxTokens: 10}, "<?php " + strings.Repeat("echo 1; ", 20), DiagnosticMaxTokensExceeded},
+	}
+	for _, c := range cases {
+		p := New(c.options)
+		sourceFile := p.ParseSourceFile([]byte(c.source), "")
+		diagnostics := p.Diagnostics()
+		if len(diagnostics) != 1 || diagnostics[0].Kind != c.kind {
+			t.Errorf("%s: expected a %d diagnostic, got %v", c.name, c.kind, diagnostics)
+		}
+		if sourceFile.EndOfFileToken.Kind != lexer.EndOfFileToken || sourceFile.EndOfFileToken.Cat != lexer.TokenCatNormal {
+			t.Errorf("%s: expected a partial tree ending at the end of file", c.name)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	p := New(Options{})
+	p.ParseSourceFileContext(ctx, []byte("<?php "+strings.Repeat("echo 1; ", 1000)), "")
+	if diagnostics := p.Diagnostics(); len(diagnostics) != 1 || diagnostics[0].Kind != DiagnosticParseCanceled {
+		t.Errorf("expected the parse to be canceled, got %v", diagnostics)
+	}
+
+	// the limits stop the lexer too
+	yields := []byte("<?php " + strings.Repeat("yield ", 200000))
+	p = New(Options{Timeout: time.Nanosecond})
+	p.ParseSourceFile(yields, "")
+	if diagnostics := p.Diagnostics(); len(diagnostics) != 1 || diagnostics[0].Kind != DiagnosticParseCanceled || diagnostics[0].Start >= len(yields) {
+		t.Errorf("expected the timeout to stop the lexer, got %v", diagnostics)
+	}
+	if len(p.stream.Tokens) > 2*checkContextEvery {
+		t.Errorf("expected the lexer to stop after the timeout, got %d tokens", len(p.stream.Tokens))
+	}
+	p = New(Options{MaxTokens: 10})
+	p.ParseSourceFile(yields, "")
+	if diagnostics := p.Diagnostics(); len(diagnostics) != 1 || diagnostics[0].Kind != DiagnosticMaxTokensExceeded {
+		t.Errorf("expected too many tokens, got %v", diagnostics)
+	}
+	if len(p.stream.Tokens) != 10 {
+		t.Errorf("expected the lexer to stop at 10 tokens, got %d tokens", len(p.stream.Tokens))
+	}
+
+	// the limits are inclusive: `((1));` nests 4 levels and has 7 tokens
+	// and the end of file
+	for _, c := range []struct {
+		options  Options
+		exceeded lexer.DiagnosticKind
+	}{
+		{Options{MaxDepth: 4, MaxTokens: 8}, 0},
+		{Options{MaxDepth: 3}, DiagnosticMaxDepthExceeded},
+		{Options{MaxTokens: 7}, DiagnosticMaxTokensExceeded},
+	} {
+		p = New(c.options)
+		p.ParseSourceFile([]byte("<?php ((1));"), "")
+		diagnostics := p.Diagnostics()
+		if c.exceeded == 0 && len(diagnostics) != 0 || c.exceeded != 0 && (len(diagnostics) != 1 || diagnostics[0].Kind != c.exceeded) {
+			t.Errorf("%+v: unexpected diagnostics %v", c.options, diagnostics)
+		}
+	}
+	// one level per nested parenthesis, statement and block, and two for
+	// the innermost statement `1;`
+	depth := 50
+	for _, source := range []string{
+		"<?php " + strings.Repeat("(", depth-2) + "1" + strings.Repeat(")", depth-2) + ";",
+		"<?php " + strings.Repeat("if (1) ", depth-2) + "1;",
+		"<?php " + strings.Repeat("{", depth-2) + "1;" + strings.Repeat("}", depth-2),
+	} {
+		p = New(Options{MaxDepth: depth})
+		p.ParseSourceFile([]byte(source), "")
+		if diagnostics := p.Diagnostics(); len(diagnostics) != 0 {
+			t.Errorf("%s: unexpected diagnostics %v", source, diagnostics)
+		}
+		p = New(Options{MaxDepth: depth - 1})
+		p.ParseSourceFile([]byte(source), "")
+		if diagnostics := p.Diagnostics(); len(diagnostics) != 1 || diagnostics[0].Kind != DiagnosticMaxDepthExceeded {
+			t.Errorf("%s: expected the depth to be exceeded, got %v", source, diagnostics)
+		}
+	}
+
+	// limits not reached
+	p = New(Options{MaxDepth: 20, MaxTokens: 100, Timeout: time.Minute})
+	p.ParseSourceFile([]byte("<?php if ($a) { echo [1, [2]]; }"), "")
+	if diagnostics := p.Diagnostics(); len(diagnostics) != 0 {
+		t.Errorf("unexpected diagnostics %v", diagnostics)
+	}
+}
+
+func TestTrace(t *testing.T) {
+	var events []string
+	p := New(Options{})
+	p.Trace = func(event TraceEvent) {
+		events = append(events, event.String())
+	}
+	p.ParseSourceFile([]byte("<?php\nfunction a( {\n  echo 1 }\n)"), "")
+	expected := []string{
+		"push SourceElements [SourceElements]",
+		"lookahead {Name, AbstractKeyword, AndKeyword, ... 79 more} at 6 after FunctionKeyword: true",
+		"missing CloseParenToken at 17 [SourceElements]",
+		"push BlockStatements [SourceElements|BlockStatements]",
+		"missing SemicolonToken at 28 [SourceElements|BlockStatements]",
+		"pop BlockStatements [SourceElements]",
+		"skipped CloseParenToken at 31 [SourceElements]",
+		"pop SourceElements []",
+	}
+	if strings.Join(events, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("expected the events:\n%s\ngot:\n%s", strings.Join(expected, "\n"), strings.Join(events, "\n"))
+	}
+
+	// Reset keeps the hook
+	p.Reset()
+	events = nil
+	p.ParseSourceFile([]byte("<?php }"), "")
+	if len(events) == 0 {
+		t.Errorf("expected events after Reset")
+	}
+}
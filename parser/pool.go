@@ -0,0 +1,43 @@
+package parser
+
+import (
+	"sync"
+
+	"github.com/emilioastarita/gphp/ast"
+)
+
+// Pool shares parsers configured with the same options between goroutines,
+// it is safe for concurrent use.
+type Pool struct {
+	options Options
+	pool    sync.Pool
+}
+
+func NewPool(opts Options) *Pool {
+	pool := &Pool{options: opts}
+	pool.pool.New = func() interface{} {
+		return New(opts)
+	}
+	return pool
+}
+
+// Get returns a parser of the pool, it must be given back with Put.
+func (pool *Pool) Get() *Parser {
+	return pool.pool.Get().(*Parser)
+}
+
+// Put resets p and gives it back to the pool, trees returned by p are
+// still valid but can't be reparsed by it. Put drops the Trace hook of p,
+// the next caller of Get must not be traced by it.
+func (pool *Pool) Put(p *Parser) {
+	p.Reset()
+	p.Trace = nil
+	pool.pool.Put(p)
+}
+
+// ParseSourceFile parses source with a parser of the pool.
+func (pool *Pool) ParseSourceFile(source []byte, uri string) *ast.SourceFileNode {
+	p := pool.Get()
+	defer pool.Put(p)
+	return p.ParseSourceFile(source, uri)
+}
@@ -0,0 +1,126 @@
+package parser
+
+import (
+	"github.com/emilioastarita/gphp/lexer"
+)
+
+// The tolerant parser only knows which tokens can start or end a list, so
+// a block missing its close brace takes every statement up to the end of
+// file. Code is indented though: with Options.IndentRecovery a declaration
+// starting a line less indented than the elements of the open blocks
+// can't belong to them, it closes those blocks instead and the error stays
+// local. Blocks whose elements aren't indented are never closed early.
+
+// unknownIndent is the indentation of a block before its first element.
+const unknownIndent = -1
+
+// isBraceList reports whether the elements of a list are enclosed in
+// braces.
+func isBraceList(context ParseContext) bool {
+	switch context {
+	case BlockStatements, ClassMembers, InterfaceMembers, TraitMembers, SwitchStatementElements:
+		return true
+	}
+	return false
+}
+
+// isDeclarationStart reports whether the current token starts a
+// declaration usually written at the top level of a file.
+func (p *Parser) isDeclarationStart() bool {
+	switch p.token.Kind {
+	case lexer.ClassKeyword, lexer.AbstractKeyword, lexer.FinalKeyword,
+		lexer.InterfaceKeyword, lexer.TraitKeyword:
+		return true
+	case lexer.FunctionKeyword:
+		// not an anonymous function
+		return p.lookahead(nameOrKeywordOrReservedWordTokens) ||
+			p.lookahead(lexer.AmpersandToken, nameOrKeywordOrReservedWordTokens)
+	case lexer.NamespaceKeyword:
+		// not a namespace\name
+		return !p.lookahead(lexer.BackslashToken)
+	}
+	return false
+}
+
+// lineIndent returns the indentation of the line of a token and whether
+// the token is the first one of its line.
+func (p *Parser) lineIndent(token *lexer.Token) (int, bool) {
+	lineStart := token.Start
+	for lineStart > 0 && p.source[lineStart-1] != '\n' && p.source[lineStart-1] != '\r' {
+		lineStart--
+	}
+	indent := lineStart
+	for indent < token.Start && (p.source[indent] == ' ' || p.source[indent] == '\t') {
+		indent++
+	}
+	return indent - lineStart, indent == token.Start
+}
+
+// blockIndent returns the indentation of the elements of the innermost
+// open block.
+func (p *Parser) blockIndent() int {
+	if len(p.blockIndents) == 0 {
+		return unknownIndent
+	}
+	return p.blockIndents[len(p.blockIndents)-1]
+}
+
+// declarationKeywords are the kinds isDeclarationStart can accept.
+var declarationKeywords = lexer.NewTokenKindSet(lexer.ClassKeyword, lexer.AbstractKeyword, lexer.FinalKeyword,
+	lexer.InterfaceKeyword, lexer.TraitKeyword, lexer.FunctionKeyword, lexer.NamespaceKeyword)
+
+// hasUnclosedBlock reports whether a brace or a paren of the tokens isn't
+// closed. The blocks of a file without one are never closed early, so the
+// indentation isn't looked at.
+func hasUnclosedBlock(tokens []*lexer.Token) bool {
+	var open []lexer.TokenKind
+	for _, token := range tokens {
+		switch token.Kind {
+		case lexer.OpenBraceToken, lexer.OpenBraceDollarToken, lexer.DollarOpenBraceToken:
+			open = append(open, lexer.CloseBraceToken)
+		case lexer.OpenParenToken:
+			open = append(open, lexer.CloseParenToken)
+		case lexer.CloseBraceToken, lexer.CloseParenToken:
+			if len(open) == 0 {
+				// a close without an open doesn't take the next statements
+				continue
+			}
+			if open[len(open)-1] != token.Kind {
+				return true
+			}
+			open = open[:len(open)-1]
+		}
+	}
+	return len(open) > 0
+}
+
+// closesOpenBlock reports whether the current token is a declaration less
+// indented than the elements of the innermost open block, so it ends that
+// block and the lists inside it.
+func (p *Parser) closesOpenBlock() bool {
+	indent := p.blockIndent()
+	if !p.unclosedBlock || indent <= 0 || !declarationKeywords.Contains(p.token.Kind) {
+		return false
+	}
+	column, startsLine := p.lineIndent(p.token)
+	return startsLine && column < indent && p.isDeclarationStart()
+}
+
+// isBlockOpenBrace reports whether the current token is a brace ending its
+// line. It opens a block, e.g. after a missing close paren in
+// `if ($a {`, it isn't the brace of a `$a{0}` subscript.
+func (p *Parser) isBlockOpenBrace() bool {
+	if p.token.Kind != lexer.OpenBraceToken {
+		return false
+	}
+	for i := p.token.FullStart + p.token.Length; i < len(p.source); i++ {
+		switch p.source[i] {
+		case ' ', '\t':
+			continue
+		case '\n', '\r':
+			return true
+		}
+		return false
+	}
+	return false
+}
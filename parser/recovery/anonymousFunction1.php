@@ -0,0 +1,6 @@
+<?php
+function a() {
+    $b = 1;
+function () {};
+namespace\c();
+}
@@ -0,0 +1,7 @@
+<?php
+class A {
+    public $a;
+
+class B {
+    public $b;
+}
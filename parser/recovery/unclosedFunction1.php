@@ -0,0 +1,8 @@
+<?php
+function a() {
+    if ($a) {
+        echo 1;
+    
+
+function b() {
+}
@@ -0,0 +1,12 @@
+<?php
+class A {
+    function a() {
+        $a = 1;
+
+    function b() {
+        return 2;
+    }
+}
+
+function c() {
+}
@@ -0,0 +1,7 @@
+<?php
+function a() {
+    if ($a {
+        echo 1;
+    }
+    echo 2;
+}
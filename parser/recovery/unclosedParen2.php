@@ -0,0 +1,4 @@
+<?php
+while ($a->b {
+    echo $a{0};
+}
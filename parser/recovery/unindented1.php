@@ -0,0 +1,8 @@
+<?php
+namespace A {
+class B {
+    function c() {
+
+function d() {
+}
+}
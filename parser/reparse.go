@@ -0,0 +1,247 @@
+package parser
+
+import (
+	"context"
+	"sort"
+
+	"github.com/emilioastarita/gphp/ast"
+	"github.com/emilioastarita/gphp/lexer"
+)
+
+// Edit replaces OldLength bytes at Start with NewText. Offsets are offsets
+// of the FileContents of the tree being edited.
+type Edit struct {
+	Start     int
+	OldLength int
+	NewText   []byte
+}
+
+// listElement records a node parsed by parseList: the tokens it spans and
+// the state of the parser when it was parsed. Its parse depends only on
+// those, so it can be reused when they did not change.
+type listElement struct {
+	node               ast.Node
+	listParseContext   ParseContext
+	parseContext       ParseContext
+	objectCreationExpr bool
+	blockIndent        int
+	first              int
+	last               int
+}
+
+// reuseLookahead is the number of tokens after a list element the parser
+// may have looked at while parsing it: the token that ends it plus the
+// tokens seen by lookahead.
+const reuseLookahead = 3
+
+// reuse holds the list elements of the previous tree that can be moved to
+// the new one.
+type reuse struct {
+	candidates map[int]listElement
+	// old records sorted by first token, to find the elements nested in a
+	// reused one
+	records []listElement
+	// the kinds given by the parser to the old tokens
+	renamedKinds map[*lexer.Token]lexer.TokenKind
+	// the edit changed the old tokens in [changedStart, changedEnd), the
+	// tokens after changedEnd are now tokenDelta positions away and their
+	// text byteDelta bytes away
+	changedStart int
+	changedEnd   int
+	tokenDelta   int
+	byteDelta    int
+}
+
+// Reparse applies edit to old, the last tree returned by p, and parses the
+// result reusing the statements, class members and function bodies not
+// touched by the edit, following the node reuse of mstpp. The nodes moved
+// to the new tree are updated in place, so old must not be used anymore.
+// When old was not parsed by p the edited source is parsed from scratch.
+func (p *Parser) Reparse(old *ast.SourceFileNode, edit Edit) *ast.SourceFileNode {
+	oldContents := old.FileContents
+	if edit.Start < 0 || edit.OldLength < 0 || edit.Start+edit.OldLength > len(oldContents) {
+		panic("parser: edit out of range")
+	}
+	if p.tree != old || p.stream == nil {
+		source := make([]byte, 0, len(oldContents)-edit.OldLength+len(edit.NewText))
+		source = append(source, oldContents[:edit.Start]...)
+		source = append(source, edit.NewText...)
+		source = append(source, oldContents[edit.Start+edit.OldLength:]...)
+		return p.ParseSourceFile(source, old.Uri)
+	}
+
+	// the stream is edited, old can't be reparsed twice
+	p.tree = nil
+
+	// the tokens renamed by the parser get back their kind, the ones of
+	// the reused elements are renamed again
+	renamedKinds := make(map[*lexer.Token]lexer.TokenKind, len(p.renamedTokens))
+	for token, kind := range p.renamedTokens {
+		renamedKinds[token] = token.Kind
+		token.Kind = kind
+	}
+
+	oldTokens := p.stream.Tokens
+	p.stream.ApplyEdit(edit.Start, edit.OldLength, edit.NewText)
+	newTokens := p.stream.Tokens
+
+	// ApplyEdit keeps the tokens before and after the relexed ones
+	prefix := 0
+	for prefix < len(oldTokens) && prefix < len(newTokens) && oldTokens[prefix] == newTokens[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(oldTokens)-prefix && suffix < len(newTokens)-prefix &&
+		oldTokens[len(oldTokens)-1-suffix] == newTokens[len(newTokens)-1-suffix] {
+		suffix++
+	}
+
+	r := &reuse{
+		candidates:   map[int]listElement{},
+		records:      p.listElements,
+		renamedKinds: renamedKinds,
+		changedStart: prefix,
+		changedEnd:   len(oldTokens) - suffix,
+		tokenDelta:   len(newTokens) - len(oldTokens),
+		byteDelta:    len(edit.NewText) - edit.OldLength,
+	}
+	sort.SliceStable(r.records, func(i, j int) bool { return r.records[i].first < r.records[j].first })
+	oldEditEnd := edit.Start + edit.OldLength
+	if p.options.IndentRecovery && hasUnclosedBlock(newTokens) != p.unclosedBlock {
+		// the blocks of the elements could be closed early now, or not
+		// anymore
+		r.records = nil
+	}
+	for _, element := range r.records {
+		if element.first >= r.changedEnd && p.options.IndentRecovery {
+			// the indentation of the blocks of an element is read from the
+			// line of its first token, the edit must not be on that line
+			oldStart := newTokens[element.first+r.tokenDelta].Start - r.byteDelta
+			if !containsNewLine(oldContents[oldEditEnd:oldStart]) {
+				continue
+			}
+		}
+		if element.last+reuseLookahead < r.changedStart || element.first >= r.changedEnd {
+			r.candidates[element.first] = element
+		}
+	}
+
+	p.reuse = r
+	defer p.begin(context.Background())()
+	sourceFile := p.parse(old.Uri)
+	p.reuse = nil
+	return sourceFile
+}
+
+func containsNewLine(text []byte) bool {
+	for _, char := range text {
+		if char == '\n' || char == '\r' {
+			return true
+		}
+	}
+	return false
+}
+
+// reuseListElement returns the element of the previous tree starting at
+// the current token if it was parsed in the same state, and moves past
+// its tokens.
+func (p *Parser) reuseListElement(listParseContext ParseContext) ast.Node {
+	r := p.reuse
+	index := p.tokenIndex()
+	oldIndex := index
+	isAfterEdit := index >= r.changedStart
+	if isAfterEdit {
+		if index < r.changedEnd+r.tokenDelta {
+			return nil
+		}
+		oldIndex = index - r.tokenDelta
+	}
+	element, ok := r.candidates[oldIndex]
+	if !ok || element.listParseContext != listParseContext || element.parseContext != p.currentParseContext ||
+		element.objectCreationExpr != p.isParsingObjectCreationExpression || element.blockIndent != p.blockIndent() {
+		return nil
+	}
+	delete(r.candidates, oldIndex)
+
+	shift := index - oldIndex
+	if isAfterEdit && r.byteDelta != 0 {
+		shiftSyntheticTokens(element.node, r.byteDelta)
+	}
+	// the elements nested in the reused one can be reused by the next edit
+	i := sort.Search(len(r.records), func(i int) bool { return r.records[i].first >= element.first })
+	for ; i < len(r.records) && r.records[i].first <= element.last; i++ {
+		if nested := r.records[i]; nested.last <= element.last {
+			nested.first += shift
+			nested.last += shift
+			p.listElements = append(p.listElements, nested)
+		}
+	}
+
+	for _, token := range p.stream.Tokens[element.first+shift : element.last+shift+1] {
+		if kind, ok := r.renamedKinds[token]; ok {
+			p.renameToken(token, kind)
+		}
+	}
+
+	p.stream.Pos = element.last + shift + 1
+	p.advanceToken()
+	return element.node
+}
+
+// renameToken changes the kind of a token, e.g. of a keyword used as a
+// name. Tokens of the stream are kept by Reparse, their original kind is
+// recorded to parse them again.
+func (p *Parser) renameToken(token *lexer.Token, kind lexer.TokenKind) {
+	if p.renamedTokens == nil {
+		p.renamedTokens = map[*lexer.Token]lexer.TokenKind{}
+	}
+	if _, ok := p.renamedTokens[token]; !ok && token.Cat == lexer.TokenCatNormal {
+		p.renamedTokens[token] = token.Kind
+	}
+	token.Kind = kind
+}
+
+// tokenIndex returns the index of the current token in the stream.
+func (p *Parser) tokenIndex() int {
+	if p.stream.Pos > 0 && p.stream.Tokens[p.stream.Pos-1] == p.token {
+		return p.stream.Pos - 1
+	}
+	return p.stream.EofPos
+}
+
+// shiftSyntheticTokens moves the missing and skipped tokens of a node
+// after an edit. They are not shared with the token stream, where ApplyEdit
+// already moved the other tokens.
+func shiftSyntheticTokens(node ast.Node, delta int) {
+	seen := map[*lexer.Token]bool{}
+	forEachToken(node, func(token *lexer.Token) {
+		if token.Cat != lexer.TokenCatNormal && !seen[token] {
+			seen[token] = true
+			token.FullStart += delta
+			token.Start += delta
+		}
+	})
+}
+
+// forEachToken calls fn for every token of a child, a node, a token or a
+// list of them, and of its descendants.
+func forEachToken(child interface{}, fn func(*lexer.Token)) {
+	switch child := child.(type) {
+	case *lexer.Token:
+		if child != nil {
+			fn(child)
+		}
+	case ast.Node:
+		for _, c := range child.Children() {
+			forEachToken(c, fn)
+		}
+	case []ast.Node:
+		for _, node := range child {
+			forEachToken(node, fn)
+		}
+	case []*lexer.Token:
+		for _, token := range child {
+			forEachToken(token, fn)
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/emilioastarita/gphp/ast"
+	"github.com/emilioastarita/gphp/lexer"
+)
+
+// TraceKind is the kind of a TraceEvent.
+type TraceKind int
+
+const (
+	// TracePush is a list context entered by parseList.
+	TracePush TraceKind = iota
+	// TracePop is a list context left by parseList.
+	TracePop
+	// TraceSkipped is a token no context accepts, kept as a SkippedNode.
+	TraceSkipped
+	// TraceMissing is a token expected but not found, synthesized with
+	// zero length.
+	TraceMissing
+	// TraceLookahead is a check of the tokens following the current one.
+	TraceLookahead
+)
+
+var traceKindNames = [...]string{"push", "pop", "skipped", "missing", "lookahead"}
+
+func (k TraceKind) String() string {
+	if k < 0 || int(k) >= len(traceKindNames) {
+		return fmt.Sprintf("TraceKind(%d)", int(k))
+	}
+	return traceKindNames[k]
+}
+
+// TraceEvent is a decision of the parser reported to Parser.Trace.
+type TraceEvent struct {
+	Kind TraceKind
+	// Context is the list context pushed or popped.
+	Context ParseContext
+	// Contexts are the bits of the list contexts open after the event.
+	Contexts ParseContext
+	// Token is the skipped or missing token, or the current token of a
+	// lookahead.
+	Token *lexer.Token
+	// Expected are the kinds of a lookahead, each one a lexer.TokenKind or
+	// a []lexer.TokenKind of alternatives.
+	Expected []interface{}
+	// Matched is the result of a lookahead.
+	Matched bool
+}
+
+func (e TraceEvent) String() string {
+	switch e.Kind {
+	case TracePush, TracePop:
+		return fmt.Sprintf("%s %s [%s]", e.Kind, e.Context, contextsString(e.Contexts))
+	case TraceLookahead:
+		expected := make([]string, len(e.Expected))
+		for i, kind := range e.Expected {
+			expected[i] = expectedString(kind)
+		}
+		return fmt.Sprintf("%s %s at %d after %s: %t", e.Kind, strings.Join(expected, " "), e.Token.Start, e.Token.Kind, e.Matched)
+	}
+	return fmt.Sprintf("%s %s at %d [%s]", e.Kind, e.Token.Kind, e.Token.Start, contextsString(e.Contexts))
+}
+
+var parseContextNames = [...]string{"SourceElements", "BlockStatements", "ClassMembers", "IfClause2Elements",
+	"SwitchStatementElements", "CaseStatementElements", "WhileStatementElements", "ForStatementElements",
+	"ForeachStatementElements", "DeclareStatementElements", "InterfaceMembers", "TraitMembers"}
+
+// String returns the name of a list context, not of a set of bits.
+func (c ParseContext) String() string {
+	if int(c) >= len(parseContextNames) {
+		return fmt.Sprintf("ParseContext(%d)", uint(c))
+	}
+	return parseContextNames[c]
+}
+
+// contextsString returns the names of the list contexts set in bits.
+func contextsString(bits ParseContext) string {
+	var names []string
+	for context := ParseContext(0); context < Count; context++ {
+		if bits&(1<<context) != 0 {
+			names = append(names, context.String())
+		}
+	}
+	return strings.Join(names, "|")
+}
+
+// expectedString returns a kind of a lookahead, the alternatives of long
+// lists are elided.
+func expectedString(kind interface{}) string {
+	kinds, ok := kind.([]lexer.TokenKind)
+	if !ok {
+		return fmt.Sprint(kind)
+	}
+	const shown = 3
+	names := make([]string, 0, shown+1)
+	for i, k := range kinds {
+		if i == shown {
+			names = append(names, fmt.Sprintf("... %d more", len(kinds)-shown))
+			break
+		}
+		names = append(names, k.String())
+	}
+	return "{" + strings.Join(names, ", ") + "}"
+}
+
+// trace reports an event when a trace hook is set.
+func (p *Parser) trace(event TraceEvent) {
+	if p.Trace == nil {
+		return
+	}
+	event.Contexts = p.currentParseContext
+	p.Trace(event)
+}
+
+// newMissingToken returns a missing token of kind at fullStart and traces
+// it.
+func (p *Parser) newMissingToken(kind lexer.TokenKind, fullStart int, parentNode ast.Node) *ast.Missing {
+	missing := ast.NewMissingToken(kind, fullStart, parentNode)
+	p.trace(TraceEvent{Kind: TraceMissing, Token: missing.Token})
+	return missing
+}
+
+// newSkippedNode returns token as a skipped node and traces it.
+func (p *Parser) newSkippedNode(token *lexer.Token) *ast.SkippedNode {
+	skipped := ast.NewSkippedNode(token)
+	p.trace(TraceEvent{Kind: TraceSkipped, Token: skipped.Token})
+	return skipped
+}
@@ -0,0 +1,135 @@
+package printer
+
+import (
+	"github.com/emilioastarita/gphp/ast"
+	"github.com/emilioastarita/gphp/lexer"
+)
+
+// statementLists are the fields holding the statements of a kind, a list
+// when the statements are in a file, a block or after a colon.
+var statementLists = map[ast.NodeKind]string{
+	ast.KindSourceFileNode:        "statementList",
+	ast.KindCompoundStatementNode: "statements",
+	ast.KindClassMembersNode:      "classMemberDeclarations",
+	ast.KindInterfaceMembers:      "interfaceMemberDeclarations",
+	ast.KindTraitMembers:          "traitMemberDeclarations",
+	ast.KindSwitchStatementNode:   "caseStatements",
+	ast.KindCaseStatementNode:     "statementList",
+	ast.KindIfStatementNode:       "statements",
+	ast.KindElseIfClauseNode:      "statements",
+	ast.KindElseClauseNode:        "statements",
+	ast.KindWhileStatement:        "statements",
+	ast.KindForStatement:          "statements",
+	ast.KindForeachStatement:      "statements",
+	ast.KindDeclareStatement:      "statements",
+}
+
+// ownLineBrace tells if the open brace of a node goes on its own line, the
+// body of a named function or of a class.
+func ownLineBrace(kind ast.NodeKind, parent ast.Node) bool {
+	switch kind {
+	case ast.KindClassMembersNode:
+		return parent == nil || parent.NodeKind() != ast.KindObjectCreationExpression
+	case ast.KindInterfaceMembers, ast.KindTraitMembers:
+		return true
+	case ast.KindCompoundStatementNode:
+		if parent == nil {
+			return false
+		}
+		switch parent.NodeKind() {
+		case ast.KindFunctionDeclaration, ast.KindMethodDeclaration:
+			return true
+		}
+	}
+	return false
+}
+
+// declaration tells if a statement is set apart by blank lines.
+func declaration(statement ast.Node) bool {
+	switch statement.(type) {
+	case *ast.FunctionDeclaration, *ast.MethodDeclaration, *ast.ClassDeclaration,
+		*ast.InterfaceDeclaration, *ast.TraitDeclaration, *ast.NamespaceDefinition:
+		return true
+	}
+	return false
+}
+
+// parenAfterName are the kinds whose open paren follows a name or a
+// keyword without a space.
+var parenAfterName = map[ast.NodeKind]bool{
+	ast.KindCallExpression:           true,
+	ast.KindFunctionDeclaration:      true,
+	ast.KindMethodDeclaration:        true,
+	ast.KindArrayCreationExpression:  true,
+	ast.KindObjectCreationExpression: true,
+	ast.KindIssetIntrinsicExpression: true,
+	ast.KindEmptyIntrinsicExpression: true,
+	ast.KindUnsetIntrinsicExpression: true,
+	ast.KindListIntrinsicExpression:  true,
+	ast.KindEvalIntrinsicExpression:  true,
+	ast.KindExitIntrinsicExpression:  true,
+	ast.KindDeclareStatement:         true,
+}
+
+// space tells if a space goes between the last token written and a token
+// on the same line, first is the first byte of the token.
+func (p *printer) space(it item, first byte) bool {
+	if wordByte(p.last) && wordByte(first) || operatorByte(p.last) && operatorByte(first) {
+		// the two tokens would be read as one
+		return true
+	}
+	return !noSpaceAfter(p.prev) && !noSpaceBefore(it)
+}
+
+func noSpaceBefore(it item) bool {
+	switch it.token.Kind {
+	case lexer.CommaToken, lexer.SemicolonToken, lexer.CloseParenToken, lexer.CloseBracketToken,
+		lexer.ArrowToken, lexer.ColonColonToken, lexer.InlineHtml, lexer.ScriptSectionStartTag:
+		return true
+	case lexer.OpenParenToken:
+		return parenAfterName[it.kind]
+	case lexer.OpenBracketToken, lexer.OpenBraceToken:
+		return it.kind == ast.KindSubscriptExpression
+	case lexer.PlusPlusToken, lexer.MinusMinusToken:
+		return it.kind == ast.KindPostfixUpdateExpression
+	case lexer.ColonToken:
+		return it.kind != ast.KindTernaryExpression
+	case lexer.BackslashToken:
+		return it.field != "globalSpecifier"
+	case lexer.EqualsToken:
+		return it.kind == ast.KindDeclareDirective
+	}
+	return false
+}
+
+func noSpaceAfter(it item) bool {
+	switch it.token.Kind {
+	case lexer.OpenParenToken, lexer.OpenBracketToken, lexer.ArrowToken, lexer.ColonColonToken,
+		lexer.DollarToken, lexer.ExclamationToken, lexer.TildeToken, lexer.AtSymbolToken,
+		lexer.DotDotDotToken, lexer.BackslashToken, lexer.InlineHtml, lexer.ScriptSectionEndTag:
+		return true
+	case lexer.AmpersandToken:
+		return it.field == "byRef" || it.field == "byRefToken" || it.field == "ampersand"
+	case lexer.QuestionToken:
+		return it.kind != ast.KindTernaryExpression
+	case lexer.PlusToken, lexer.MinusToken:
+		return it.kind == ast.KindUnaryOpExpression
+	case lexer.PlusPlusToken, lexer.MinusMinusToken:
+		return it.kind == ast.KindPrefixUpdateExpression
+	case lexer.EqualsToken:
+		return it.kind == ast.KindDeclareDirective
+	}
+	return false
+}
+
+func wordByte(b byte) bool {
+	return b == '_' || b >= 'a' && b <= 'z' || b >= 'A' && b <= 'Z' || b >= '0' && b <= '9' || b >= 0x80
+}
+
+func operatorByte(b byte) bool {
+	switch b {
+	case '+', '-', '*', '/', '%', '.', '=', '<', '>', '&', '|', '^', '!', '?', '~':
+		return true
+	}
+	return false
+}
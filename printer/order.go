@@ -0,0 +1,61 @@
+package printer
+
+import "github.com/emilioastarita/gphp/ast"
+
+// sourceOrder are the fields of the kinds whose children aren't in the
+// source in the order of their fields, see ast.Node.Children. The printer
+// needs the order of the source to write the tokens that have no position.
+var sourceOrder = map[ast.NodeKind][]string{
+	ast.KindAnonymousFunctionCreationExpression: {"staticModifier", "functionKeyword", "byRefToken", "name", "openParen", "parameters", "closeParen", "anonymousFunctionUseClause", "colonToken", "questionToken", "returnType", "compoundStatementOrSemicolon"},
+	ast.KindAnonymousFunctionUseClause:          {"useKeyword", "openParen", "useVariableNameList", "closeParen"},
+	ast.KindArrayCreationExpression:             {"arrayKeyword", "openParenOrBracket", "arrayElements", "closeParenOrBracket"},
+	ast.KindArrayElement:                        {"elementKey", "arrowToken", "byRef", "elementValue"},
+	ast.KindCallExpression:                      {"callableExpression", "openParen", "argumentExpressionList", "closeParen"},
+	ast.KindCaseStatementNode:                   {"caseKeyword", "expression", "defaultLabelTerminator", "statementList"},
+	ast.KindCastExpression:                      {"openParen", "castType", "closeParen", "operand"},
+	ast.KindCatchClause:                         {"catch", "openParen", "qualifiedName", "variableName", "closeParen", "compoundStatement"},
+	ast.KindClassConstDeclaration:               {"modifiers", "constKeyword", "constElements", "semicolon"},
+	ast.KindDeclareStatement:                    {"declareKeyword", "openParen", "declareDirective", "closeParen", "colon", "statements", "enddeclareKeyword", "semicolon"},
+	ast.KindElseIfClauseNode:                    {"elseIfKeyword", "openParen", "expression", "closeParen", "colon", "statements"},
+	ast.KindEmptyIntrinsicExpression:            {"emptyKeyword", "openParen", "expression", "closeParen"},
+	ast.KindEvalIntrinsicExpression:             {"evalKeyword", "openParen", "expression", "closeParen"},
+	ast.KindExitIntrinsicExpression:             {"exitOrDieKeyword", "openParen", "expression", "closeParen"},
+	ast.KindForeachStatement:                    {"foreach", "openParen", "forEachCollectionName", "asKeyword", "foreachKey", "foreachValue", "closeParen", "colon", "statements", "endForeach", "endForeachSemicolon"},
+	ast.KindForeachValue:                        {"ampersand", "expression"},
+	ast.KindIfStatementNode:                     {"ifKeyword", "openParen", "expression", "closeParen", "colon", "statements", "elseIfClauses", "elseClause", "endifKeyword", "semicolon"},
+	ast.KindInlineHtml:                          {"scriptSectionPrependedText", "scriptSectionEndTag", "text", "scriptSectionStartTag", "echoStatement"},
+	ast.KindInterfaceMembers:                    {"openBrace", "interfaceMemberDeclarations", "closeBrace"},
+	ast.KindIssetIntrinsicExpression:            {"issetKeyword", "openParen", "expressions", "closeParen"},
+	ast.KindListIntrinsicExpression:             {"listKeyword", "openParen", "listElements", "closeParen"},
+	ast.KindMemberAccessExpression:              {"dereferencableExpression", "arrowToken", "memberName"},
+	ast.KindMethodDeclaration:                   {"modifiers", "functionKeyword", "byRefToken", "name", "openParen", "parameters", "closeParen", "colonToken", "questionToken", "returnType", "compoundStatementOrSemicolon"},
+	ast.KindNamespaceUseDeclaration:             {"useKeyword", "functionOrConst", "useClauses", "semicolon"},
+	ast.KindParenthesizedExpression:             {"openParen", "expression", "closeParen"},
+	ast.KindPostfixUpdateExpression:             {"operand", "incrementOrDecrementOperator"},
+	ast.KindSubscriptExpression:                 {"postfixExpression", "openBracketOrBrace", "accessExpression", "closeBracketOrBrace"},
+	ast.KindTernaryExpression:                   {"condition", "questionToken", "ifExpression", "colonToken", "elseExpression"},
+	ast.KindThrowStatement:                      {"throwKeyword", "expression", "semicolon"},
+	ast.KindTraitMembers:                        {"openBrace", "traitMemberDeclarations", "closeBrace"},
+	ast.KindUnsetIntrinsicExpression:            {"unsetKeyword", "openParen", "expressions", "closeParen"},
+}
+
+// order returns the indexes of the children of a node in the order of the
+// source.
+func order(kind ast.NodeKind, names []string) []int {
+	indexes := make([]int, 0, len(names))
+	fields, ok := sourceOrder[kind]
+	if !ok {
+		for i := range names {
+			indexes = append(indexes, i)
+		}
+		return indexes
+	}
+	for _, field := range fields {
+		for i, name := range names {
+			if name == field {
+				indexes = append(indexes, i)
+			}
+		}
+	}
+	return indexes
+}
@@ -0,0 +1,299 @@
+// Package printer writes trees of the ast package as php. The subtrees
+// read by the parser keep their text, trivia and comments included, and
+// the nodes made or changed in memory, see the build package, are written
+// with a simple formatting: a statement on each line, the blocks indented
+// and the functions and classes with their braces on their own lines.
+package printer
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"strings"
+
+	"github.com/emilioastarita/gphp/ast"
+	"github.com/emilioastarita/gphp/lexer"
+)
+
+// Config changes how Fprint writes the new nodes.
+type Config struct {
+	// Indent is a level of indentation. When empty it is the indentation
+	// of the first indented line of the source, or four spaces.
+	Indent string
+	// Source is the text the tokens of the node were read from, for a node
+	// that isn't in a SourceFileNode with its FileContents.
+	Source []byte
+}
+
+// item is a token to write and where it is in the tree.
+type item struct {
+	token *lexer.Token
+	// kind is the kind of the node holding the token and field the name of
+	// its child, for a token in a TokenNode the ones of the TokenNode.
+	kind  ast.NodeKind
+	field string
+}
+
+type printer struct {
+	w      *bufio.Writer
+	source []byte
+	indent string
+	// file is set when the node is a SourceFileNode, its first token is
+	// written with its leading trivia
+	file bool
+
+	prev  item // the last token written
+	depth int
+	// line is set when the next token starts a line and blank when a blank
+	// line goes before it
+	line  bool
+	blank bool
+	// last is the last byte written and newlines the number of line feeds
+	// ending the text written
+	last     byte
+	newlines int
+	err      error
+}
+
+// Fprint writes a node as php. A token read by the parser that follows the
+// token written before it in the source is written with its leading trivia,
+// so an untouched subtree is written as it was read. The other tokens get a
+// space, a line feed and the indentation when they need one, their leading
+// trivia is kept when it has a line feed or a comment. The leading trivia
+// of the first token is left out, unless the node is a SourceFileNode. A
+// nil cfg is the zero Config.
+func Fprint(w io.Writer, node ast.Node, cfg *Config) error {
+	if node == nil || node.Children() == nil {
+		return nil
+	}
+	if cfg == nil {
+		cfg = &Config{}
+	}
+	p := &printer{w: bufio.NewWriter(w), source: cfg.Source, indent: cfg.Indent}
+	for n := node; n != nil; n = n.Parent() {
+		if file, ok := n.(*ast.SourceFileNode); ok {
+			if file.FileContents != nil {
+				p.source = file.FileContents
+			}
+			break
+		}
+	}
+	if p.indent == "" {
+		p.indent = indentation(p.source)
+	}
+	_, p.file = node.(*ast.SourceFileNode)
+	p.depth = depth(node)
+	p.child(nil, "", node)
+	if p.err != nil {
+		return p.err
+	}
+	return p.w.Flush()
+}
+
+// indentation returns the indentation of the first indented line of a
+// source, four spaces when there is none.
+func indentation(source []byte) string {
+	for _, line := range strings.Split(string(source), "\n") {
+		indent := line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+		if indent != "" && strings.TrimSpace(line) != "" {
+			return indent
+		}
+	}
+	return "    "
+}
+
+// depth returns the number of blocks holding a node.
+func depth(node ast.Node) int {
+	depth := 0
+	for child, parent := node, node.Parent(); parent != nil; child, parent = parent, parent.Parent() {
+		field, ok := statementLists[parent.NodeKind()]
+		if !ok || parent.NodeKind() == ast.KindSourceFileNode {
+			continue
+		}
+		names := parent.ChildNames()
+		for i, c := range parent.Children() {
+			if statements, ok := c.([]ast.Node); ok && names[i] == field {
+				for _, statement := range statements {
+					if statement == child {
+						depth++
+					}
+				}
+			}
+		}
+	}
+	return depth
+}
+
+// child writes a child of a node, as returned by Children.
+func (p *printer) child(parent ast.Node, field string, child interface{}) {
+	kind := ast.NodeKind(-1)
+	if parent != nil {
+		kind = parent.NodeKind()
+	}
+	switch child := child.(type) {
+	case *lexer.Token:
+		if child != nil {
+			p.token(item{child, kind, field})
+		}
+	case *ast.TokenNode:
+		if child != nil {
+			p.token(item{child.Token, kind, field})
+		}
+	case *ast.SkippedNode:
+		if child != nil {
+			p.token(item{child.Token, kind, field})
+		}
+	case *ast.Missing:
+		if child != nil {
+			p.token(item{child.Token, kind, field})
+		}
+	case ast.Node:
+		if child != nil {
+			p.node(parent, child)
+		}
+	case []ast.Node:
+		for _, node := range child {
+			p.child(parent, field, node)
+		}
+	case []*lexer.Token:
+		for _, token := range child {
+			p.child(parent, field, token)
+		}
+	}
+}
+
+func (p *printer) node(parent, n ast.Node) {
+	children := n.Children()
+	if children == nil {
+		return
+	}
+	kind := n.NodeKind()
+	names := n.ChildNames()
+	for _, i := range order(kind, names) {
+		name := names[i]
+		if statements, ok := children[i].([]ast.Node); ok && statementLists[kind] == name {
+			p.statements(n, name, statements)
+			continue
+		}
+		if name == "openBrace" && ownLineBrace(kind, parent) {
+			p.line = true
+		}
+		p.child(n, name, children[i])
+	}
+}
+
+// statements writes a list of statements, each one on its own line and
+// indented in a block.
+func (p *printer) statements(parent ast.Node, field string, statements []ast.Node) {
+	indented := parent.NodeKind() != ast.KindSourceFileNode
+	if indented {
+		p.depth++
+	}
+	for i, statement := range statements {
+		if _, ok := statement.(*ast.InlineHtml); !ok {
+			p.line = true
+			if i > 0 {
+				_, afterHtml := statements[i-1].(*ast.InlineHtml)
+				p.blank = !afterHtml && (declaration(statement) || declaration(statements[i-1]))
+			}
+		}
+		p.child(parent, field, statement)
+	}
+	if indented {
+		p.depth--
+		p.line = true
+	}
+}
+
+func (p *printer) token(it item) {
+	token := it.token
+	switch {
+	case token == nil:
+		return
+	case token.Cat == lexer.TokenCatMissing:
+		// a missing token has no text, the next token can follow the one
+		// before it
+		return
+	case token.Cat == lexer.TokenCatSynthetic:
+		text := token.Text(nil)
+		if token.Kind == lexer.EndOfFileToken {
+			// a generated file ends with a line feed
+			if p.prev.token != nil && p.newlines == 0 && p.prev.token.Kind != lexer.InlineHtml && p.prev.token.Kind != lexer.ScriptSectionEndTag {
+				p.write("\n")
+			}
+		} else {
+			p.separate(it, "", text)
+		}
+		p.write(text)
+	case token.FullStart < 0 || token.FullStart+token.Length > len(p.source):
+		if p.err == nil {
+			p.err = errors.New("printer: a token is out of the source")
+		}
+		return
+	case p.prev.token == nil && p.file, p.adjacent(token):
+		p.write(token.FullText(p.source))
+	case p.prev.token == nil:
+		p.write(token.Text(p.source))
+	default:
+		text := token.Text(p.source)
+		p.separate(it, token.LeadingTrivia(p.source), text)
+		p.write(text)
+	}
+	p.prev = it
+	p.line, p.blank = false, false
+}
+
+// adjacent tells if a token read by the parser follows the last token
+// written in the source.
+func (p *printer) adjacent(token *lexer.Token) bool {
+	prev := p.prev.token
+	return prev != nil && prev.Cat != lexer.TokenCatSynthetic && prev.FullStart+prev.Length == token.FullStart
+}
+
+// separate writes what goes before a token that doesn't follow the last
+// token written in the source, trivia is its leading trivia.
+func (p *printer) separate(it item, trivia, text string) {
+	switch {
+	case p.line && !strings.Contains(trivia, "\n"):
+		p.newline()
+		p.write(strings.TrimLeft(trivia, " \t"))
+	case strings.Trim(trivia, " \t") != "":
+		p.write(trivia)
+	case p.prev.token != nil && !isSpace(p.last) && text != "" && p.space(it, text[0]):
+		p.write(" ")
+	}
+}
+
+// newline writes a line feed, two after a blank line, and the indentation.
+func (p *printer) newline() {
+	if p.last == 0 {
+		return
+	}
+	lines := 1
+	if p.blank {
+		lines = 2
+	}
+	for p.newlines < lines {
+		p.write("\n")
+	}
+	p.write(strings.Repeat(p.indent, p.depth))
+}
+
+func (p *printer) write(s string) {
+	if s == "" {
+		return
+	}
+	p.w.WriteString(s)
+	p.last = s[len(s)-1]
+	text := strings.TrimRight(s, "\r\n")
+	feeds := strings.Count(s[len(text):], "\n")
+	if text == "" {
+		p.newlines += feeds
+	} else {
+		p.newlines = feeds
+	}
+}
+
+func isSpace(b byte) bool {
+	return b == ' ' || b == '\t' || b == '\n' || b == '\r'
+}
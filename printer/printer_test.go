@@ -0,0 +1,142 @@
+package printer_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/emilioastarita/gphp/ast"
+	"github.com/emilioastarita/gphp/ast/build"
+	"github.com/emilioastarita/gphp/parser"
+	"github.com/emilioastarita/gphp/printer"
+)
+
+// TestRoundTrip writes the parsed cases, the broken ones too, and expects
+// their source back.
+func TestRoundTrip(t *testing.T) {
+	sourceFiles, _ := filepath.Glob("../parser/cases/*.php")
+	recoveryFiles, _ := filepath.Glob("../parser/recovery/*.php")
+	if len(sourceFiles) == 0 {
+		t.Fatal("no cases found")
+	}
+	for _, sourceFileName := range append(sourceFiles, recoveryFiles...) {
+		source, _ := ioutil.ReadFile(sourceFileName)
+		p := parser.Parser{}
+		sourceFile := p.ParseSourceFile(source, "")
+		var buf bytes.Buffer
+		if err := printer.Fprint(&buf, sourceFile, nil); err != nil {
+			t.Errorf("%s: %v", sourceFileName, err)
+		} else if !bytes.Equal(buf.Bytes(), source) {
+			t.Errorf("%s: the text differs:\n%s", sourceFileName, buf.Bytes())
+		}
+	}
+}
+
+func fprint(t *testing.T, node ast.Node, cfg *printer.Config) string {
+	var buf bytes.Buffer
+	if err := printer.Fprint(&buf, node, cfg); err != nil {
+		t.Fatal(err)
+	}
+	return buf.String()
+}
+
+// TestBuild writes a built file and parses it back.
+func TestBuild(t *testing.T) {
+	class := build.Class("User").Extends("Model").Implements("JsonSerializable").
+		Const("LIMIT", build.Int(-1)).
+		Property(build.Property("name").Private().Default(build.String("x"))).
+		Method(build.Method("name").Returns("?string").Param(build.Param("id").Type("int"), build.Param("rest").Variadic()).Body(
+			build.If(build.Not(build.Call(build.Name("isset"), build.Var("id"))), build.Throw(build.New(build.Name(`\Exception`), build.String("no id")))).
+				Else(build.Expr(build.Assign(build.Var("x"), build.Paren(build.Binary(build.Var("id"), "+", build.Int(1)))))).
+				Node(),
+			build.Return(build.Prop(build.Var("this"), "name")),
+		)).
+		Node()
+	file := build.File(build.Namespace(`App\Models`), build.Use(`Foo\Bar`, "Baz"), class)
+	expected := strings.Join([]string{
+		"<?php",
+		`namespace App\Models;`,
+		"",
+		`use Foo\Bar as Baz;`,
+		"",
+		"class User extends Model implements JsonSerializable",
+		"{",
+		"    const LIMIT = -1;",
+		"    private $name = 'x';",
+		"",
+		"    public function name(int $id, ...$rest): ?string",
+		"    {",
+		"        if (!isset($id)) {",
+		`            throw new \Exception('no id');`,
+		"        } else {",
+		"            $x = ($id + 1);",
+		"        }",
+		"        return $this->name;",
+		"    }",
+		"}",
+		"",
+	}, "\n")
+	actual := fprint(t, file, nil)
+	if actual != expected {
+		t.Fatalf("expected:\n%s\nactual:\n%s", expected, actual)
+	}
+	p := parser.Parser{}
+	p.ParseSourceFile([]byte(actual), "")
+	if diagnostics := p.Diagnostics(); len(diagnostics) != 0 {
+		t.Errorf("the text doesn't parse: %v", diagnostics)
+	}
+}
+
+// TestModified changes a parsed tree, the untouched text stays and the new
+// statements get the indentation of the file.
+func TestModified(t *testing.T) {
+	source := "<?php\n// a comment\nclass A {\n\t/** doc */\n\tfunction f($x) {\n\t\treturn g( $x ,1 );\n\t}\n}\n"
+	p := parser.Parser{}
+	file := p.ParseSourceFile([]byte(source), "")
+	class := file.StatementList[1].(*ast.ClassDeclaration)
+	members := class.ClassMembers.(*ast.ClassMembersNode)
+	method := members.ClassMemberDeclarations[0].(*ast.MethodDeclaration)
+	body := method.CompoundStatementOrSemicolon.(*ast.CompoundStatementNode)
+	ret := body.Statements[0].(*ast.ReturnStatement)
+	arguments := ret.Expression.(*ast.CallExpression).ArgumentExpressionList.(*ast.ArgumentExpressionList)
+	arguments.Child[2] = build.Var("y")
+	body.Statements = append([]ast.Node{build.Expr(build.Assign(build.Var("y"), build.Int(2)))}, body.Statements...)
+	members.ClassMemberDeclarations = append(members.ClassMemberDeclarations, build.Method("h").Body(build.Return(nil)).Node())
+
+	expected := "<?php\n// a comment\nclass A {\n\t/** doc */\n\tfunction f($x) {\n\t\t$y = 2;\n\t\treturn g( $x , $y);\n\t}\n\n\tpublic function h()\n\t{\n\t\treturn;\n\t}\n}\n"
+	if actual := fprint(t, file, nil); actual != expected {
+		t.Errorf("expected:\n%s\nactual:\n%s", expected, actual)
+	}
+	// a node is written without its leading trivia, its new statements
+	// indented as in the file
+	expected = "function f($x) {\n\t\t$y = 2;\n\t\treturn g( $x , $y);\n\t}"
+	if actual := fprint(t, method, nil); actual != expected {
+		t.Errorf("expected:\n%s\nactual:\n%s", expected, actual)
+	}
+	expected = "function f($x) {\n  $y = 2;\n\t\treturn g( $x , $y);\n\t}"
+	if actual := fprint(t, method, &printer.Config{Indent: " "}); actual != expected {
+		t.Errorf("expected:\n%s\nactual:\n%s", expected, actual)
+	}
+}
+
+// TestMoved writes original tokens out of their order without the spaces
+// of their old places.
+func TestMoved(t *testing.T) {
+	p := parser.Parser{}
+	file := p.ParseSourceFile([]byte("<?php f($a, $b);"), "")
+	call := file.StatementList[1].(*ast.ExpressionStatement).Expression[0].(*ast.CallExpression)
+	arguments := call.ArgumentExpressionList.(*ast.ArgumentExpressionList)
+	arguments.Child[0], arguments.Child[2] = arguments.Child[2], arguments.Child[0]
+	if actual := fprint(t, call, nil); actual != "f($b, $a)" {
+		t.Errorf("expected f($b, $a), got %q", actual)
+	}
+	if err := printer.Fprint(&bytes.Buffer{}, call, &printer.Config{}); err != nil {
+		t.Error(err)
+	}
+	call.SetParent(nil)
+	if err := printer.Fprint(&bytes.Buffer{}, call, nil); err == nil {
+		t.Error("expected an error for tokens without a source")
+	}
+}